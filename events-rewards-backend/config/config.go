@@ -2,8 +2,10 @@ package config
 
 import (
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -16,6 +18,9 @@ type Config struct {
 	JWTSecret   string
 	UploadPath  string
 	MinIO       MinIOConfig
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For and
+	// X-Real-IP headers are believed when working out a client's address
+	TrustedProxies []*net.IPNet
 }
 
 type MinIOConfig struct {
@@ -46,9 +51,39 @@ func Load() *Config {
 			BucketName: getEnv("MINIO_BUCKET_NAME", "events-rewards"),
 			UseSSL:     useSSL,
 		},
+		TrustedProxies: parseTrustedProxies(getEnv("TRUSTED_PROXIES", "")),
 	}
 }
 
+// parseTrustedProxies reads a comma-separated list of IP addresses and CIDR ranges
+func parseTrustedProxies(value string) []*net.IPNet {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				log.Fatalf("Invalid TRUSTED_PROXIES entry: %s", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES entry: %s", entry)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
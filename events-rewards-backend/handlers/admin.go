@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type AdminHandler struct {
+	db *gorm.DB
+}
+
+func NewAdminHandler(db *gorm.DB) *AdminHandler {
+	return &AdminHandler{db: db}
+}
+
+// UpdateUserRole - Change a user's role (admin only)
+func (h *AdminHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !models.IsValidRole(req.Role) {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Role must be one of: user, staff, organizer, editor, admin")
+		return
+	}
+
+	var user models.User
+	if err := h.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "User not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch user")
+		}
+		return
+	}
+
+	if err := h.db.Model(&user).Update("role", req.Role).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update user role")
+		return
+	}
+
+	// The new role takes effect once the user's access token is refreshed
+	utils.SuccessResponse(w, map[string]interface{}{
+		"message": "User role updated successfully",
+		"user": map[string]interface{}{
+			"id":    user.ID,
+			"email": user.Email,
+			"role":  req.Role,
+		},
+	})
+}
+
+// UpdateUserTier - Set or clear the tier that selects a user's spin policy (admin only)
+func (h *AdminHandler) UpdateUserTier(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req models.UpdateUserTierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var tier interface{}
+	if req.Tier != nil && strings.TrimSpace(*req.Tier) != "" {
+		tier = strings.TrimSpace(*req.Tier)
+	}
+
+	result := h.db.Model(&models.User{}).Where("id = ?", userID).Update("tier", tier)
+	if result.Error != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update user tier")
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.ErrorResponse(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"message": "User tier updated successfully",
+		"user": map[string]interface{}{
+			"id":   userID,
+			"tier": tier,
+		},
+	})
+}
+
+// ListSpinEvents - Search the spin audit log (admin only). Filters: user_id,
+// campaign_id, outcome, device_id, ip_address, and from/to as RFC3339 times.
+func (h *AdminHandler) ListSpinEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, limit := parsePagination(r, 50)
+
+	events := h.db.Model(&models.SpinEvent{})
+	for _, filter := range []string{"user_id", "campaign_id"} {
+		if value := query.Get(filter); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.ErrorResponse(w, http.StatusBadRequest, "Invalid "+filter)
+				return
+			}
+			events = events.Where(filter+" = ?", id)
+		}
+	}
+	for _, filter := range []string{"outcome", "device_id", "ip_address"} {
+		if value := query.Get(filter); value != "" {
+			events = events.Where(filter+" = ?", value)
+		}
+	}
+	if from := query.Get("from"); from != "" {
+		fromTime, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid from format. Use RFC3339 format")
+			return
+		}
+		events = events.Where("created_at >= ?", fromTime)
+	}
+	if to := query.Get("to"); to != "" {
+		toTime, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid to format. Use RFC3339 format")
+			return
+		}
+		events = events.Where("created_at < ?", toTime)
+	}
+
+	var totalCount int64
+	if err := events.Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin events")
+		return
+	}
+
+	var spins []models.SpinEvent
+	if err := events.
+		Preload("Reward").
+		Preload("AwardedReward").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&spins).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin events")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"spin_events": spins,
+		"pagination":  paginationResponse(page, limit, totalCount),
+	})
+}
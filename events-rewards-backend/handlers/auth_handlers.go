@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	_ "image/jpeg"
 	_ "image/png"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -20,10 +22,12 @@ import (
 )
 
 type AuthHandler struct {
-	authService  *services.AuthService
-	minioService *services.MinIOService
-	db           *gorm.DB
-	uploadPath   string
+	authService    *services.AuthService
+	minioService   *services.MinIOService
+	tokenBlacklist *services.TokenBlacklistService
+	db             *gorm.DB
+	bus            *services.EventBus
+	uploadPath     string
 }
 
 type RegisterRequest struct {
@@ -54,7 +58,7 @@ type AuthResponse struct {
 	Error   string                 `json:"error,omitempty"`
 }
 
-func NewAuthHandler(db *gorm.DB, minioService *services.MinIOService) *AuthHandler {
+func NewAuthHandler(db *gorm.DB, minioService *services.MinIOService, tokenBlacklist *services.TokenBlacklistService, bus *services.EventBus) *AuthHandler {
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		jwtSecret = "default-secret-key"
@@ -65,11 +69,19 @@ func NewAuthHandler(db *gorm.DB, minioService *services.MinIOService) *AuthHandl
 		uploadPath = "./uploads"
 	}
 
+	authService := services.NewAuthService(db, jwtSecret)
+	authService.SetTokenLifetimes(
+		getDurationEnv("ACCESS_TOKEN_TTL", services.DefaultAccessTokenTTL),
+		getDurationEnv("REFRESH_TOKEN_TTL", services.DefaultRefreshTokenTTL),
+	)
+
 	return &AuthHandler{
-		authService:  services.NewAuthService(db, jwtSecret),
-		minioService: minioService,
-		db:           db,
-		uploadPath:   uploadPath,
+		authService:    authService,
+		minioService:   minioService,
+		tokenBlacklist: tokenBlacklist,
+		db:             db,
+		bus:            bus,
+		uploadPath:     uploadPath,
 	}
 }
 
@@ -148,6 +160,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		DeviceID:     &req.DeviceID,
 		IsVerified:   false,
 		IsActive:     true,
+		Role:         models.RoleUser,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
@@ -157,8 +170,8 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := h.authService.GenerateJWT(&user)
+	// Generate access and refresh tokens
+	tokens, err := h.authService.IssueTokenPair(&user)
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to generate authentication token")
 		return
@@ -168,9 +181,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	user.PasswordHash = ""
 
 	utils.SuccessResponse(w, map[string]interface{}{
-		"user":    user,
-		"token":   token,
-		"message": "Registration successful. Please complete identity verification.",
+		"user":               user,
+		"token":              tokens.AccessToken,
+		"expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+		"message":            "Registration successful. Please complete identity verification.",
 	})
 }
 
@@ -215,8 +231,12 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := h.authService.GenerateJWT(&user)
+	if req.DeviceID != "" {
+		user.DeviceID = &req.DeviceID
+	}
+
+	// Generate access and refresh tokens
+	tokens, err := h.authService.IssueTokenPair(&user)
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to generate authentication token")
 		return
@@ -226,9 +246,52 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	user.PasswordHash = ""
 
 	utils.SuccessResponse(w, map[string]interface{}{
-		"user":    user,
-		"token":   token,
-		"message": "Login successful",
+		"user":               user,
+		"token":              tokens.AccessToken,
+		"expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+		"message":            "Login successful",
+	})
+}
+
+// RefreshToken - Exchange a refresh token for a new access/refresh token pair
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.RefreshToken == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Refresh token is required")
+		return
+	}
+
+	tokens, user, err := h.authService.RotateRefreshToken(req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			log.Printf("Security: refresh token reuse detected, token family revoked")
+			utils.ErrorResponse(w, http.StatusUnauthorized, "Refresh token has already been used. Please log in again")
+		case errors.Is(err, services.ErrRefreshTokenExpired):
+			utils.ErrorResponse(w, http.StatusUnauthorized, "Refresh token has expired. Please log in again")
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			utils.ErrorResponse(w, http.StatusUnauthorized, "Invalid refresh token")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to refresh authentication token")
+		}
+		return
+	}
+
+	user.PasswordHash = ""
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"user":               user,
+		"token":              tokens.AccessToken,
+		"expires_at":         tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
 	})
 }
 
@@ -247,22 +310,31 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get token from header for logging purposes
+	// Get token from header so it can be revoked
 	authHeader := r.Header.Get("Authorization")
 	var tokenString string
 	if strings.HasPrefix(authHeader, "Bearer ") {
 		tokenString = strings.TrimPrefix(authHeader, "Bearer ")
 	}
 
-	// If you want to parse the token for logging or other purposes
-	if tokenString != "" {
-		claims, err := h.authService.ParseToken(tokenString)
-		if err == nil {
-			// Log token expiration info
-			if claims.ExpiresAt != nil {
-				fmt.Printf("User %s logged out. Token expires at: %s\n",
-					userID, claims.ExpiresAt.Time.Format(time.RFC3339))
-			}
+	// The middleware has already validated the token, so its claims can be trusted here
+	claims, err := h.authService.ParseToken(tokenString)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid token")
+		return
+	}
+
+	if err := h.tokenBlacklist.Revoke(claims.ID, userID, claims.ExpiresAt.Time); err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to revoke token")
+		return
+	}
+
+	// Optionally end the refresh token session as well
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err == nil && req.RefreshToken != "" {
+		if err := h.authService.RevokeRefreshToken(req.RefreshToken, userID); err != nil && !errors.Is(err, services.ErrInvalidRefreshToken) {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to revoke refresh token")
+			return
 		}
 	}
 
@@ -543,6 +615,7 @@ func (h *AuthHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 			"phone":       user.Phone,
 			"is_verified": user.IsVerified,
 			"is_active":   user.IsActive,
+			"role":        user.Role,
 			"selfie_url":  selfieURL,
 			"voice_url":   voiceURL,
 			"has_selfie":  user.SelfiePath != nil && *user.SelfiePath != "",
@@ -746,6 +819,16 @@ func (h *AuthHandler) validateBasicVoice(file multipart.File, header *multipart.
 	return false
 }
 
+// getDurationEnv reads a duration such as "15m" or "720h" from the environment
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 // isValidEmail validates email format
 func isValidEmail(email string) bool {
 	return strings.Contains(email, "@") && strings.Contains(email, ".")
@@ -816,13 +899,20 @@ func (h *AuthHandler) attemptAutoVerification(userID uuid.UUID) bool {
 	hasVoice := user.VoicePath != nil && *user.VoicePath != ""
 
 	if hasSelfie && hasVoice && !user.IsVerified {
-		// Auto-verify the user
-		if err := h.db.Model(&user).Updates(map[string]interface{}{
+		// Auto-verify the user; only the request that flips the flag announces it
+		result := h.db.Model(&user).Where("is_verified = ?", false).Updates(map[string]interface{}{
 			"is_verified": true,
 			"updated_at":  time.Now(),
-		}).Error; err != nil {
+		})
+		if result.Error != nil {
 			return false
 		}
+		if result.RowsAffected == 1 {
+			h.bus.Publish(services.EventUserVerified, map[string]interface{}{
+				"user_id":      userID,
+				"reference_id": userID,
+			})
+		}
 		return true
 	}
 
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BonusSpinHandler struct {
+	db    *gorm.DB
+	bonus *services.BonusSpinService
+}
+
+func NewBonusSpinHandler(db *gorm.DB, bonus *services.BonusSpinService) *BonusSpinHandler {
+	return &BonusSpinHandler{db: db, bonus: bonus}
+}
+
+// GetMyBonusSpins - Get the user's earned bonus spins, newest first
+func (h *BonusSpinHandler) GetMyBonusSpins(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+	query := h.db.Model(&models.BonusSpinGrant{}).Where("user_id = ?", userID)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch bonus spins")
+		return
+	}
+
+	var grants []models.BonusSpinGrant
+	if err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&grants).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch bonus spins")
+		return
+	}
+
+	available, err := h.bonus.Available(h.db, userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch bonus spins")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"available":  available,
+		"grants":     grants,
+		"pagination": paginationResponse(page, limit, totalCount),
+	})
+}
+
+// ListRules - List the bonus spin rule for every action, including built-in defaults (admin only)
+func (h *BonusSpinHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules := models.DefaultBonusSpinRules()
+
+	var configured []models.BonusSpinRule
+	if err := h.db.Find(&configured).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch bonus spin rules")
+		return
+	}
+	for _, rule := range configured {
+		rules[rule.Action] = rule
+	}
+
+	list := make([]models.BonusSpinRule, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, rule)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Action < list[j].Action })
+
+	utils.SuccessResponse(w, list)
+}
+
+// UpdateRule - Replace the bonus spin rule for an action (admin only)
+func (h *BonusSpinHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	action := mux.Vars(r)["action"]
+	if !models.IsValidBonusAction(action) {
+		utils.ErrorResponse(w, http.StatusNotFound, "Unknown bonus spin action")
+		return
+	}
+
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateBonusSpinRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.SpinsGranted < 1 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "spins_granted must be at least 1")
+		return
+	}
+	if (req.DailyCap != nil && *req.DailyCap < 0) || (req.TotalCap != nil && *req.TotalCap < 0) {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Caps cannot be negative")
+		return
+	}
+
+	now := time.Now()
+	rule := models.BonusSpinRule{
+		ID:           uuid.New(),
+		Action:       action,
+		SpinsGranted: req.SpinsGranted,
+		DailyCap:     req.DailyCap,
+		TotalCap:     req.TotalCap,
+		IsActive:     req.IsActive,
+		UpdatedBy:    &adminID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "action"}},
+		DoUpdates: clause.AssignmentColumns([]string{"spins_granted", "daily_cap", "total_cap", "is_active", "updated_by", "updated_at"}),
+	}).Create(&rule).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update bonus spin rule")
+		return
+	}
+
+	saved, err := h.bonus.Rule(h.db, action)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch bonus spin rule")
+		return
+	}
+
+	utils.SuccessResponse(w, saved)
+}
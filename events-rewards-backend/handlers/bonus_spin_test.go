@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// These tests rely on the built-in bonus spin rules, so the test database must
+// not have rules of its own configured
+
+func TestBonusSpinGrantCaps(t *testing.T) {
+	db := openTestDB(t)
+	bonus := services.NewBonusSpinService(db)
+	user := createTestUser(t, db, nil)
+
+	// News reads earn one spin each, at most three times a day
+	granted := 0
+	for i := 0; i < 4; i++ {
+		spins, err := bonus.Grant(user.ID, models.BonusActionNewsRead, uuid.New(), 1)
+		if err != nil {
+			t.Fatalf("Grant: %v", err)
+		}
+		granted += spins
+	}
+	if granted != 3 {
+		t.Errorf("news reads granted %d spins, want 3", granted)
+	}
+
+	// Verification earns three spins once per user, whatever the reference
+	first, err := bonus.Grant(user.ID, models.BonusActionIdentityVerification, uuid.New(), 1)
+	if err != nil {
+		t.Fatalf("Grant: %v", err)
+	}
+	second, err := bonus.Grant(user.ID, models.BonusActionIdentityVerification, uuid.New(), 1)
+	if err != nil {
+		t.Fatalf("Grant: %v", err)
+	}
+	if first != 3 || second != 0 {
+		t.Errorf("verification grants were %d and %d spins, want 3 and 0", first, second)
+	}
+
+	available, err := bonus.Available(db, user.ID)
+	if err != nil {
+		t.Fatalf("Available: %v", err)
+	}
+	if available != 6 {
+		t.Errorf("%d bonus spins available, want 6", available)
+	}
+}
+
+func TestBonusSpinGrantPaysEachReferenceOnce(t *testing.T) {
+	db := openTestDB(t)
+	bonus := services.NewBonusSpinService(db)
+	user := createTestUser(t, db, nil)
+
+	eventID := uuid.New()
+	for i, want := range []int{1, 0} {
+		spins, err := bonus.Grant(user.ID, models.BonusActionEventRegistration, eventID, 1)
+		if err != nil {
+			t.Fatalf("Grant: %v", err)
+		}
+		if spins != want {
+			t.Errorf("grant %d for the same registration was %d spins, want %d", i+1, spins, want)
+		}
+	}
+}
+
+func TestBonusSpinGrantPurchased(t *testing.T) {
+	db := openTestDB(t)
+	bonus := services.NewBonusSpinService(db)
+	user := createTestUser(t, db, nil)
+
+	// Bought spins are paid for, so the engagement caps never hold them back
+	for i := 0; i < 3; i++ {
+		var granted int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			granted, err = bonus.GrantPurchased(tx, user.ID, uuid.New(), 2)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("GrantPurchased: %v", err)
+		}
+		if granted != 2 {
+			t.Errorf("order %d granted %d spins, want 2", i+1, granted)
+		}
+	}
+
+	// The same order can never be granted twice
+	orderID := uuid.New()
+	grant := func() error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			_, err := bonus.GrantPurchased(tx, user.ID, orderID, 1)
+			return err
+		})
+	}
+	if err := grant(); err != nil {
+		t.Fatalf("GrantPurchased: %v", err)
+	}
+	if err := grant(); err == nil {
+		t.Error("granting the same order twice succeeded")
+	}
+
+	available, err := bonus.Available(db, user.ID)
+	if err != nil {
+		t.Fatalf("Available: %v", err)
+	}
+	if available != 7 {
+		t.Errorf("%d bonus spins available, want 7", available)
+	}
+}
+
+func TestBonusSpinConsumeOldestGrantFirst(t *testing.T) {
+	db := openTestDB(t)
+	bonus := services.NewBonusSpinService(db)
+	user := createTestUser(t, db, nil)
+
+	now := time.Now()
+	older := models.BonusSpinGrant{ID: uuid.New(), UserID: user.ID, Action: models.BonusActionNewsRead, ReferenceID: uuid.New(), Spins: 1, CreatedAt: now.Add(-time.Hour)}
+	newer := models.BonusSpinGrant{ID: uuid.New(), UserID: user.ID, Action: models.BonusActionNewsRead, ReferenceID: uuid.New(), Spins: 2, CreatedAt: now}
+	// Insert the newer grant first so row order cannot stand in for age
+	for _, grant := range []*models.BonusSpinGrant{&newer, &older} {
+		if err := db.Create(grant).Error; err != nil {
+			t.Fatalf("create bonus spin grant: %v", err)
+		}
+	}
+
+	consume := func() bool {
+		t.Helper()
+		consumed, err := bonus.Consume(db, user.ID)
+		if err != nil {
+			t.Fatalf("Consume: %v", err)
+		}
+		return consumed
+	}
+	used := func(grant models.BonusSpinGrant) int {
+		t.Helper()
+		var reloaded models.BonusSpinGrant
+		if err := db.First(&reloaded, "id = ?", grant.ID).Error; err != nil {
+			t.Fatalf("reload bonus spin grant: %v", err)
+		}
+		return reloaded.SpinsUsed
+	}
+
+	if !consume() {
+		t.Fatal("Consume found no spins")
+	}
+	if used(older) != 1 || used(newer) != 0 {
+		t.Errorf("first spin came from the newer grant (older used %d, newer used %d)", used(older), used(newer))
+	}
+
+	for i := 0; i < 2; i++ {
+		if !consume() {
+			t.Fatalf("Consume %d found no spins", i+2)
+		}
+	}
+	if used(newer) != 2 {
+		t.Errorf("newer grant used %d spins, want 2", used(newer))
+	}
+
+	if consume() {
+		t.Error("Consume spent a spin after every grant was used up")
+	}
+}
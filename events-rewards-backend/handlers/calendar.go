@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// calendarFeedPastWindow is how far back public feeds include events, so
+// entries do not vanish from calendars as soon as they start
+const calendarFeedPastWindow = 30 * 24 * time.Hour
+
+type CalendarHandler struct {
+	db *gorm.DB
+}
+
+func NewCalendarHandler(db *gorm.DB) *CalendarHandler {
+	return &CalendarHandler{db: db}
+}
+
+// writeCalendar sends a finished calendar as an .ics download
+func writeCalendar(w http.ResponseWriter, cal *services.ICalendar, filename string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(cal.Bytes())
+}
+
+// GetEventCalendar - Export a single event as an .ics file
+func (h *CalendarHandler) GetEventCalendar(w http.ResponseWriter, r *http.Request) {
+	var event models.Event
+	if err := h.db.Where("id = ? AND is_active = ?", mux.Vars(r)["id"], true).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Event not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event")
+		}
+		return
+	}
+
+	cal := services.NewICalendar(event.Title)
+	cal.AddEvent(&event)
+	writeCalendar(w, cal, "event-"+event.ID.String()+".ics")
+}
+
+// GetCategoryCalendar - Public subscribable feed of a category's events
+func (h *CalendarHandler) GetCategoryCalendar(w http.ResponseWriter, r *http.Request) {
+	category := mux.Vars(r)["category"]
+
+	// Inactive events stay in the feed so subscribers see them as cancelled
+	var events []models.Event
+	if err := h.db.
+		Where("category = ? AND event_date >= ?", category, time.Now().Add(-calendarFeedPastWindow)).
+		Order("event_date").
+		Find(&events).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch events")
+		return
+	}
+
+	cal := services.NewICalendar(category + " events")
+	for i := range events {
+		cal.AddEvent(&events[i])
+	}
+	writeCalendar(w, cal, "category-events.ics")
+}
+
+// GetUserCalendar - Subscribable feed of the events a user registered for, authenticated by the feed token
+func (h *CalendarHandler) GetUserCalendar(w http.ResponseWriter, r *http.Request) {
+	var feed models.CalendarFeed
+	if err := h.db.Where("token = ?", mux.Vars(r)["token"]).First(&feed).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Calendar feed not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch calendar feed")
+		}
+		return
+	}
+
+	registrations, err := loadUserRegistrations(h.db, feed.UserID.String())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch registrations")
+		return
+	}
+
+	cal := services.NewICalendar("My events")
+	for i := range registrations {
+		registration := &registrations[i]
+		// Deleted events do not load, and a waitlist place is not a booking
+		if registration.Event.ID == uuid.Nil || registration.Status == models.RegistrationStatusWaitlisted {
+			continue
+		}
+		cal.AddEvent(&registration.Event)
+	}
+	writeCalendar(w, cal, "my-events.ics")
+}
+
+// GetCalendarFeed - Get the user's calendar subscription URL, creating it on first use
+func (h *CalendarHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	feed := models.CalendarFeed{
+		ID:     uuid.New(),
+		UserID: userID,
+		Token:  generateCalendarToken(),
+	}
+	if err := h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&feed).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create calendar feed")
+		return
+	}
+	if err := h.db.Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch calendar feed")
+		return
+	}
+
+	utils.SuccessResponse(w, calendarFeedResponse(r, &feed))
+}
+
+// ResetCalendarFeed - Issue a new calendar subscription URL, revoking the old one
+func (h *CalendarHandler) ResetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	feed := models.CalendarFeed{
+		ID:     uuid.New(),
+		UserID: userID,
+		Token:  generateCalendarToken(),
+	}
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "updated_at"}),
+	}).Create(&feed).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to reset calendar feed")
+		return
+	}
+
+	utils.SuccessResponse(w, calendarFeedResponse(r, &feed))
+}
+
+// calendarFeedResponse describes a feed with its absolute subscription URLs
+func calendarFeedResponse(r *http.Request, feed *models.CalendarFeed) map[string]interface{} {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	path := r.Host + "/api/v1/calendar/feeds/" + feed.Token + ".ics"
+
+	return map[string]interface{}{
+		"url":        scheme + "://" + path,
+		"webcal_url": "webcal://" + path,
+		"updated_at": feed.UpdatedAt,
+	}
+}
+
+// generateCalendarToken returns a random, URL-safe feed token
+func generateCalendarToken() string {
+	bytes := make([]byte, 24)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type CampaignHandler struct {
+	db *gorm.DB
+}
+
+func NewCampaignHandler(db *gorm.DB) *CampaignHandler {
+	return &CampaignHandler{db: db}
+}
+
+// ListCampaigns - Get all campaigns with their reward pools (admin only)
+func (h *CampaignHandler) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	var campaigns []models.Campaign
+	if err := h.db.Preload("Rewards.Reward").Order("starts_at DESC").Find(&campaigns).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch campaigns")
+		return
+	}
+
+	utils.SuccessResponse(w, campaigns)
+}
+
+// GetCampaign - Get a single campaign with its reward pool (admin only)
+func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	var campaign models.Campaign
+	if err := h.db.Preload("Rewards.Reward").Where("id = ?", mux.Vars(r)["id"]).First(&campaign).Error; err != nil {
+		writeCampaignError(w, err, "Failed to fetch campaign")
+		return
+	}
+
+	utils.SuccessResponse(w, campaign)
+}
+
+// GetActiveCampaigns - Get campaigns that are currently running
+func (h *CampaignHandler) GetActiveCampaigns(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+
+	var campaigns []models.Campaign
+	result := h.db.
+		Preload("Rewards.Reward").
+		Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, now, now).
+		Order("ends_at ASC").
+		Find(&campaigns)
+
+	if result.Error != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch campaigns")
+		return
+	}
+
+	// Don't expose campaign weights to the frontend, same as the base reward list
+	type CampaignRewardResponse struct {
+		ID          uuid.UUID `json:"id"`
+		Name        string    `json:"name"`
+		Description *string   `json:"description"`
+		RewardType  *string   `json:"reward_type"`
+		Value       *float64  `json:"value"`
+	}
+
+	type CampaignResponse struct {
+		ID                   uuid.UUID                `json:"id"`
+		Name                 string                   `json:"name"`
+		Description          *string                  `json:"description"`
+		BannerImage          *string                  `json:"banner_image"`
+		StartsAt             time.Time                `json:"starts_at"`
+		EndsAt               time.Time                `json:"ends_at"`
+		DailySpinLimit       *int                     `json:"daily_spin_limit"`
+		TotalSpinLimit       *int                     `json:"total_spin_limit"`
+		RequiresVerification bool                     `json:"requires_verification"`
+		Rewards              []CampaignRewardResponse `json:"rewards"`
+	}
+
+	responseCampaigns := []CampaignResponse{}
+	for _, campaign := range campaigns {
+		response := CampaignResponse{
+			ID:                   campaign.ID,
+			Name:                 campaign.Name,
+			Description:          campaign.Description,
+			BannerImage:          campaign.BannerImage,
+			StartsAt:             campaign.StartsAt,
+			EndsAt:               campaign.EndsAt,
+			DailySpinLimit:       campaign.DailySpinLimit,
+			TotalSpinLimit:       campaign.TotalSpinLimit,
+			RequiresVerification: campaign.RequiresVerification,
+			Rewards:              []CampaignRewardResponse{},
+		}
+		for _, entry := range campaign.Rewards {
+			if !entry.Reward.IsActive {
+				continue
+			}
+			response.Rewards = append(response.Rewards, CampaignRewardResponse{
+				ID:          entry.Reward.ID,
+				Name:        entry.Reward.Name,
+				Description: entry.Reward.Description,
+				RewardType:  entry.Reward.RewardType,
+				Value:       entry.Reward.Value,
+			})
+		}
+		responseCampaigns = append(responseCampaigns, response)
+	}
+
+	utils.SuccessResponse(w, responseCampaigns)
+}
+
+// CreateCampaign - Create a lucky draw campaign (admin only)
+func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid starts_at format. Use RFC3339 format")
+		return
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid ends_at format. Use RFC3339 format")
+		return
+	}
+
+	campaign := models.Campaign{
+		ID:                   uuid.New(),
+		Name:                 strings.TrimSpace(req.Name),
+		Description:          req.Description,
+		BannerImage:          req.BannerImage,
+		StartsAt:             startsAt,
+		EndsAt:               endsAt,
+		IsActive:             true,
+		DailySpinLimit:       req.DailySpinLimit,
+		TotalSpinLimit:       req.TotalSpinLimit,
+		RequiresVerification: req.RequiresVerification,
+		MinAccountAgeDays:    req.MinAccountAgeDays,
+		EligibleTiers:        normalizeTierList(req.EligibleTiers),
+		CreatedBy:            &adminID,
+	}
+
+	if campaign.Name == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	if err := validateCampaign(&campaign); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.db.Create(&campaign).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create campaign")
+		return
+	}
+
+	utils.SuccessResponse(w, campaign)
+}
+
+// UpdateCampaign - Edit a campaign's schedule, limits or eligibility rules (admin only)
+func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var campaign models.Campaign
+	if err := h.db.Where("id = ?", mux.Vars(r)["id"]).First(&campaign).Error; err != nil {
+		writeCampaignError(w, err, "Failed to fetch campaign")
+		return
+	}
+
+	if req.Name != nil {
+		campaign.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		campaign.Description = req.Description
+	}
+	if req.BannerImage != nil {
+		campaign.BannerImage = req.BannerImage
+	}
+	if req.StartsAt != nil {
+		startsAt, err := time.Parse(time.RFC3339, *req.StartsAt)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid starts_at format. Use RFC3339 format")
+			return
+		}
+		campaign.StartsAt = startsAt
+	}
+	if req.EndsAt != nil {
+		endsAt, err := time.Parse(time.RFC3339, *req.EndsAt)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid ends_at format. Use RFC3339 format")
+			return
+		}
+		campaign.EndsAt = endsAt
+	}
+	if req.IsActive != nil {
+		campaign.IsActive = *req.IsActive
+	}
+	if req.DailySpinLimit != nil {
+		campaign.DailySpinLimit = req.DailySpinLimit
+	}
+	if req.TotalSpinLimit != nil {
+		campaign.TotalSpinLimit = req.TotalSpinLimit
+	}
+	if req.RequiresVerification != nil {
+		campaign.RequiresVerification = *req.RequiresVerification
+	}
+	if req.MinAccountAgeDays != nil {
+		campaign.MinAccountAgeDays = *req.MinAccountAgeDays
+	}
+	if req.EligibleTiers != nil {
+		campaign.EligibleTiers = normalizeTierList(req.EligibleTiers)
+	}
+
+	if campaign.Name == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Name cannot be empty")
+		return
+	}
+	if err := validateCampaign(&campaign); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.db.Save(&campaign).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update campaign")
+		return
+	}
+
+	utils.SuccessResponse(w, campaign)
+}
+
+// DeleteCampaign - Remove a campaign; rewards already won in it are kept (admin only)
+func (h *CampaignHandler) DeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	result := h.db.Where("id = ?", mux.Vars(r)["id"]).Delete(&models.Campaign{})
+	if result.Error != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to delete campaign")
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.ErrorResponse(w, http.StatusNotFound, "Campaign not found")
+		return
+	}
+
+	utils.MessageResponse(w, "Campaign deleted successfully")
+}
+
+// SetCampaignRewards - Replace a campaign's reward pool and weights (admin only)
+func (h *CampaignHandler) SetCampaignRewards(w http.ResponseWriter, r *http.Request) {
+	var req models.SetCampaignRewardsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Rewards) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "At least one reward is required")
+		return
+	}
+
+	entries := make([]models.CampaignReward, 0, len(req.Rewards))
+	rewardIDs := make([]uuid.UUID, 0, len(req.Rewards))
+	seen := make(map[uuid.UUID]bool)
+	var total float64
+
+	for _, input := range req.Rewards {
+		rewardID, err := uuid.Parse(input.RewardID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid reward ID: "+input.RewardID)
+			return
+		}
+		if seen[rewardID] {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Reward listed more than once: "+input.RewardID)
+			return
+		}
+		if err := validateProbability(input.Probability); err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		seen[rewardID] = true
+		total += input.Probability
+		rewardIDs = append(rewardIDs, rewardID)
+		entries = append(entries, models.CampaignReward{
+			ID:          uuid.New(),
+			RewardID:    rewardID,
+			Probability: input.Probability,
+		})
+	}
+
+	if total > 1+probabilityTolerance {
+		utils.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Campaign probabilities add up to %.4f; the total cannot exceed 1", total))
+		return
+	}
+
+	var campaign models.Campaign
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", mux.Vars(r)["id"]).First(&campaign).Error; err != nil {
+			return err
+		}
+
+		var found int64
+		if err := tx.Model(&models.Reward{}).Where("id IN ?", rewardIDs).Count(&found).Error; err != nil {
+			return err
+		}
+		if int(found) != len(rewardIDs) {
+			return errRewardValidation{"One or more rewards do not exist"}
+		}
+
+		if err := tx.Where("campaign_id = ?", campaign.ID).Delete(&models.CampaignReward{}).Error; err != nil {
+			return err
+		}
+		for i := range entries {
+			entries[i].CampaignID = campaign.ID
+		}
+		return tx.Create(&entries).Error
+	})
+
+	if err != nil {
+		writeCampaignError(w, err, "Failed to update campaign rewards")
+		return
+	}
+
+	h.db.Preload("Rewards.Reward").First(&campaign, campaign.ID)
+	utils.SuccessResponse(w, campaign)
+}
+
+func validateCampaign(campaign *models.Campaign) error {
+	if !campaign.EndsAt.After(campaign.StartsAt) {
+		return errors.New("Campaign must end after it starts")
+	}
+	if campaign.DailySpinLimit != nil && *campaign.DailySpinLimit < 0 {
+		return errors.New("Daily spin limit cannot be negative")
+	}
+	if campaign.TotalSpinLimit != nil && *campaign.TotalSpinLimit < 0 {
+		return errors.New("Total spin limit cannot be negative")
+	}
+	if campaign.MinAccountAgeDays < 0 {
+		return errors.New("Minimum account age cannot be negative")
+	}
+	return nil
+}
+
+// normalizeTierList trims a comma-separated tier list, returning nil when no tiers are left
+func normalizeTierList(tiers *string) *string {
+	if tiers == nil {
+		return nil
+	}
+
+	campaign := models.Campaign{EligibleTiers: tiers}
+	list := campaign.TierList()
+	if len(list) == 0 {
+		return nil
+	}
+
+	joined := strings.Join(list, ",")
+	return &joined
+}
+
+func writeCampaignError(w http.ResponseWriter, err error, fallback string) {
+	var validationErr errRewardValidation
+	switch {
+	case errors.As(err, &validationErr):
+		utils.ErrorResponse(w, http.StatusBadRequest, validationErr.message)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, "Campaign not found")
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, fallback)
+	}
+}
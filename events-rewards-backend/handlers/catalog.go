@@ -0,0 +1,520 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxOrderQuantity caps how many units of an item one order may take
+const maxOrderQuantity = 10
+
+var (
+	errItemUnavailable      = errors.New("catalog item unavailable")
+	errItemOutOfStock       = errors.New("catalog item out of stock")
+	errInvalidOrderStatus   = errors.New("invalid order status transition")
+	errSeatEventIneligible  = errors.New("event cannot take priority seats")
+	errPrioritySeatQuantity = errors.New("priority seats are sold one per order")
+	errSpinsUnavailable     = errors.New("extra spins cannot be granted")
+)
+
+type CatalogHandler struct {
+	db     *gorm.DB
+	ledger *services.PointsLedger
+	bus    *services.EventBus
+	bonus  *services.BonusSpinService
+}
+
+func NewCatalogHandler(db *gorm.DB, ledger *services.PointsLedger, bus *services.EventBus, bonus *services.BonusSpinService) *CatalogHandler {
+	return &CatalogHandler{db: db, ledger: ledger, bus: bus, bonus: bonus}
+}
+
+// checkSeatEvent makes sure priority seats can be sold for the event: it must
+// exist, be active and upcoming, and not sell its seats through ticket tiers
+func checkSeatEvent(db *gorm.DB, eventID uuid.UUID, now time.Time) error {
+	var event models.Event
+	if err := db.Where("id = ?", eventID).First(&event).Error; err != nil {
+		return err
+	}
+	if !event.IsActive || event.EventDate.Before(now) {
+		return errSeatEventIneligible
+	}
+
+	var tierCount int64
+	if err := db.Model(&models.TicketTier{}).Where("event_id = ?", eventID).Count(&tierCount).Error; err != nil {
+		return err
+	}
+	if tierCount > 0 {
+		return errSeatEventIneligible
+	}
+	return nil
+}
+
+// reservePrioritySeat registers the user for the event in the transaction that
+// pays for the seat. A user on the waitlist is given the seat ahead of the
+// queue. It fails with errEventFull, leaving the transaction to roll back the
+// charge, when no seat is left.
+func reservePrioritySeat(tx *gorm.DB, userID, eventID uuid.UUID, now time.Time) error {
+	var existing []models.EventRegistration
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND event_id = ?", userID, eventID).
+		Limit(1).
+		Find(&existing).Error; err != nil {
+		return err
+	}
+	if len(existing) > 0 && existing[0].Status != models.RegistrationStatusWaitlisted {
+		return errAlreadyRegistered
+	}
+
+	seated, err := takeSeat(tx, eventID, 1)
+	if err != nil {
+		return err
+	}
+	if !seated {
+		return errEventFull
+	}
+
+	if len(existing) > 0 {
+		return tx.Model(&existing[0]).Update("status", models.RegistrationStatusRegistered).Error
+	}
+	return tx.Create(&models.EventRegistration{
+		ID:               uuid.New(),
+		UserID:           userID,
+		EventID:          eventID,
+		RegistrationDate: now,
+		Status:           models.RegistrationStatusRegistered,
+	}).Error
+}
+
+// GetCatalog - Get the items users can currently buy with points
+func (h *CatalogHandler) GetCatalog(w http.ResponseWriter, r *http.Request) {
+	query := h.db.Where("is_active = ?", true)
+	if itemType := r.URL.Query().Get("type"); itemType != "" {
+		query = query.Where("item_type = ?", itemType)
+	}
+
+	var items []models.CatalogItem
+	if err := query.Order("points_cost ASC").Find(&items).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch catalog")
+		return
+	}
+
+	type CatalogItemResponse struct {
+		models.CatalogItem
+		Remaining *int `json:"remaining"`
+	}
+
+	responseItems := make([]CatalogItemResponse, 0, len(items))
+	for _, item := range items {
+		responseItems = append(responseItems, CatalogItemResponse{CatalogItem: item, Remaining: item.Remaining()})
+	}
+
+	utils.SuccessResponse(w, responseItems)
+}
+
+// PlaceOrder - Buy a catalog item with points
+func (h *CatalogHandler) PlaceOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PlaceOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	itemID, err := uuid.Parse(req.ItemID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	if req.Quantity < 1 || req.Quantity > maxOrderQuantity {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Quantity must be between 1 and 10")
+		return
+	}
+
+	var order models.CatalogOrder
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var item models.CatalogItem
+		if err := tx.Where("id = ?", itemID).First(&item).Error; err != nil {
+			return err
+		}
+		if !item.IsActive {
+			return errItemUnavailable
+		}
+		if item.ItemType == models.CatalogItemPrioritySeat {
+			if req.Quantity != 1 {
+				return errPrioritySeatQuantity
+			}
+			if item.EventID == nil {
+				return errItemUnavailable
+			}
+			if err := checkSeatEvent(tx, *item.EventID, now); err != nil {
+				if errors.Is(err, errSeatEventIneligible) {
+					return errItemUnavailable
+				}
+				return err
+			}
+		}
+
+		// Take stock with a conditional update so concurrent orders cannot oversell
+		result := tx.Model(&models.CatalogItem{}).
+			Where("id = ? AND (stock IS NULL OR total_ordered + ? <= stock)", item.ID, req.Quantity).
+			Update("total_ordered", gorm.Expr("total_ordered + ?", req.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errItemOutOfStock
+		}
+
+		order = models.CatalogOrder{
+			ID:          uuid.New(),
+			UserID:      userID,
+			ItemID:      item.ID,
+			Quantity:    req.Quantity,
+			PointsSpent: item.PointsCost * int64(req.Quantity),
+			Status:      models.OrderStatusPending,
+		}
+
+		// Priority seats and extra spins are delivered with the order, so there is nothing left for an admin to fulfil
+		if item.ItemType == models.CatalogItemPrioritySeat || item.ItemType == models.CatalogItemExtraSpin {
+			order.Status = models.OrderStatusFulfilled
+			order.FulfilledAt = &now
+		}
+		if item.ItemType == models.CatalogItemPrioritySeat {
+			if err := reservePrioritySeat(tx, userID, *item.EventID, now); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+
+		// Spins are granted with the order; if none can be, the whole order rolls back and no points are spent
+		if item.ItemType == models.CatalogItemExtraSpin {
+			granted, err := h.bonus.GrantPurchased(tx, userID, order.ID, order.Quantity)
+			if err != nil {
+				return err
+			}
+			if granted == 0 {
+				return errSpinsUnavailable
+			}
+		}
+
+		_, err := h.ledger.Debit(tx, userID, order.PointsSpent, models.PointsTxnRedemptionDebit, "catalog_order", order.ID, "Ordered "+item.Name)
+		order.Item = item
+		return err
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, errItemUnavailable):
+			utils.ErrorResponse(w, http.StatusNotFound, "Item not found or no longer available")
+		case errors.Is(err, errItemOutOfStock):
+			utils.ErrorResponse(w, http.StatusConflict, "Not enough stock left for this item")
+		case errors.Is(err, errPrioritySeatQuantity):
+			utils.ErrorResponse(w, http.StatusBadRequest, "Priority seats can only be ordered one at a time")
+		case errors.Is(err, errAlreadyRegistered):
+			utils.ErrorResponse(w, http.StatusConflict, "You are already registered for this event")
+		case errors.Is(err, errEventFull):
+			utils.ErrorResponse(w, http.StatusConflict, "This event has no seats left")
+		case errors.Is(err, errSpinsUnavailable):
+			utils.ErrorResponse(w, http.StatusConflict, "Extra spins are not available right now")
+		case errors.Is(err, services.ErrInsufficientPoints):
+			utils.ErrorResponse(w, http.StatusPaymentRequired, "Not enough points for this order")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to place order")
+		}
+		return
+	}
+
+	h.bus.Publish(services.EventCatalogOrderPlaced, map[string]interface{}{
+		"order_id":  order.ID,
+		"user_id":   order.UserID,
+		"item_id":   order.ItemID,
+		"item_type": order.Item.ItemType,
+		"quantity":  order.Quantity,
+	})
+	// A priority seat is a registration like any other, so it earns the same bonus spins
+	if order.Item.ItemType == models.CatalogItemPrioritySeat {
+		h.bus.Publish(services.EventEventRegistered, map[string]interface{}{
+			"user_id":      order.UserID,
+			"reference_id": *order.Item.EventID,
+		})
+	}
+
+	utils.SuccessResponse(w, order)
+}
+
+// GetMyOrders - Get the user's catalog orders, newest first
+func (h *CatalogHandler) GetMyOrders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := h.db.Model(&models.CatalogOrder{}).Where("user_id = ?", userID)
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var orders []models.CatalogOrder
+	if err := query.Preload("Item").Order("created_at DESC").Find(&orders).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch orders")
+		return
+	}
+
+	utils.SuccessResponse(w, orders)
+}
+
+// ListItems - Get every catalog item, including inactive ones (admin only)
+func (h *CatalogHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	var items []models.CatalogItem
+	if err := h.db.Order("created_at DESC").Find(&items).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch catalog")
+		return
+	}
+
+	utils.SuccessResponse(w, items)
+}
+
+// CreateItem - Add an item to the points catalog (admin only)
+func (h *CatalogHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCatalogItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	item := models.CatalogItem{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		ImageURL:    req.ImageURL,
+		ItemType:    req.ItemType,
+		PointsCost:  req.PointsCost,
+		Stock:       req.Stock,
+		IsActive:    true,
+	}
+
+	if item.Name == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	if !models.IsValidCatalogItemType(item.ItemType) {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Item type must be one of: voucher, merchandise, extra_spin, priority_seat")
+		return
+	}
+	if req.EventID != nil && *req.EventID != "" {
+		eventID, err := uuid.Parse(*req.EventID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid event ID")
+			return
+		}
+		item.EventID = &eventID
+	}
+	if item.ItemType == models.CatalogItemPrioritySeat {
+		if item.EventID == nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Priority seat items must name an event")
+			return
+		}
+		if err := checkSeatEvent(h.db, *item.EventID, time.Now()); err != nil {
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				utils.ErrorResponse(w, http.StatusBadRequest, "Event not found")
+			case errors.Is(err, errSeatEventIneligible):
+				utils.ErrorResponse(w, http.StatusBadRequest, "Priority seats need an active, upcoming event without ticket tiers")
+			default:
+				utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event")
+			}
+			return
+		}
+	}
+	if err := validateCatalogItem(&item); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.db.Create(&item).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create catalog item")
+		return
+	}
+
+	utils.SuccessResponse(w, item)
+}
+
+// UpdateItem - Edit a catalog item's price, stock or availability (admin only)
+func (h *CatalogHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateCatalogItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var item models.CatalogItem
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", mux.Vars(r)["id"]).First(&item).Error; err != nil {
+			return err
+		}
+
+		if req.Name != nil {
+			item.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Description != nil {
+			item.Description = req.Description
+		}
+		if req.ImageURL != nil {
+			item.ImageURL = req.ImageURL
+		}
+		if req.PointsCost != nil {
+			item.PointsCost = *req.PointsCost
+		}
+		if req.Stock != nil {
+			item.Stock = req.Stock
+		}
+		if req.IsActive != nil {
+			item.IsActive = *req.IsActive
+		}
+
+		if item.Name == "" {
+			return errRewardValidation{"Name cannot be empty"}
+		}
+		if err := validateCatalogItem(&item); err != nil {
+			return errRewardValidation{err.Error()}
+		}
+
+		return tx.Save(&item).Error
+	})
+
+	if err != nil {
+		var validationErr errRewardValidation
+		switch {
+		case errors.As(err, &validationErr):
+			utils.ErrorResponse(w, http.StatusBadRequest, validationErr.message)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, "Catalog item not found")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update catalog item")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, item)
+}
+
+// ListOrders - Get catalog orders, optionally filtered by status (admin only)
+func (h *CatalogHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r, 20)
+
+	query := h.db.Model(&models.CatalogOrder{})
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch orders")
+		return
+	}
+
+	var orders []models.CatalogOrder
+	if err := query.Preload("Item").Order("created_at ASC").Offset((page - 1) * limit).Limit(limit).Find(&orders).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch orders")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"orders":     orders,
+		"pagination": paginationResponse(page, limit, totalCount),
+	})
+}
+
+// UpdateOrderStatus - Fulfil or cancel a pending order; cancelling refunds the points
+// and returns the stock (admin only)
+func (h *CatalogHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateOrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Status != models.OrderStatusFulfilled && req.Status != models.OrderStatusCancelled {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Status must be fulfilled or cancelled")
+		return
+	}
+
+	var order models.CatalogOrder
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", mux.Vars(r)["id"]).First(&order).Error; err != nil {
+			return err
+		}
+		if order.Status != models.OrderStatusPending {
+			return errInvalidOrderStatus
+		}
+
+		now := time.Now()
+		order.Status = req.Status
+		order.Notes = req.Notes
+		if req.Status == models.OrderStatusFulfilled {
+			order.FulfilledAt = &now
+		} else {
+			order.CancelledAt = &now
+
+			if err := tx.Model(&models.CatalogItem{}).
+				Where("id = ?", order.ItemID).
+				Update("total_ordered", gorm.Expr("GREATEST(total_ordered - ?, 0)", order.Quantity)).Error; err != nil {
+				return err
+			}
+			if _, err := h.ledger.Refund(tx, order.UserID, order.PointsSpent, models.PointsTxnRedemptionRefund, "catalog_order", order.ID, "Order cancelled"); err != nil {
+				return err
+			}
+		}
+
+		return tx.Save(&order).Error
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, "Order not found")
+		case errors.Is(err, errInvalidOrderStatus):
+			utils.ErrorResponse(w, http.StatusConflict, "Only pending orders can be fulfilled or cancelled")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update order")
+		}
+		return
+	}
+
+	h.db.Preload("Item").First(&order, order.ID)
+	utils.SuccessResponse(w, order)
+}
+
+func validateCatalogItem(item *models.CatalogItem) error {
+	if item.PointsCost < 1 {
+		return errors.New("Points cost must be at least 1")
+	}
+	if item.Stock != nil && *item.Stock < item.TotalOrdered {
+		return errors.New("Stock cannot be less than the number already ordered")
+	}
+	return nil
+}
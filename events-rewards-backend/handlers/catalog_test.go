@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestPlaceOrderPrioritySeat(t *testing.T) {
+	db := openTestDB(t)
+	ledger := services.NewPointsLedger(db)
+	h := NewCatalogHandler(db, ledger, services.NewEventBus(), services.NewBonusSpinService(db))
+
+	event := createTestEvent(t, db, 1)
+	eventID := event.ID
+	item := models.CatalogItem{
+		ID:         uuid.New(),
+		Name:       "Priority seat",
+		ItemType:   models.CatalogItemPrioritySeat,
+		PointsCost: 30,
+		EventID:    &eventID,
+		IsActive:   true,
+	}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("create catalog item: %v", err)
+	}
+
+	fundedUser := func() models.User {
+		user := createTestUser(t, db, nil)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			_, err := ledger.Credit(tx, user.ID, 100, models.PointsTxnRewardCredit, "user_reward", uuid.New(), "Test credit")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("credit points: %v", err)
+		}
+		return user
+	}
+	order := func(user models.User) int {
+		body := strings.NewReader(`{"item_id": "` + item.ID.String() + `"}`)
+		req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/user/orders", body), user)
+		rec := httptest.NewRecorder()
+		h.PlaceOrder(rec, req)
+		return rec.Code
+	}
+	balance := func(user models.User) int64 {
+		balance, err := ledger.Balance(user.ID)
+		if err != nil {
+			t.Fatalf("Balance: %v", err)
+		}
+		return balance
+	}
+
+	first := fundedUser()
+	if code := order(first); code != http.StatusOK {
+		t.Fatalf("first order returned %d, want 200", code)
+	}
+	if got := balance(first); got != 70 {
+		t.Errorf("balance after order is %d, want 70", got)
+	}
+	var registrations int64
+	db.Model(&models.EventRegistration{}).Where("user_id = ? AND event_id = ? AND status = ?", first.ID, event.ID, "registered").Count(&registrations)
+	if registrations != 1 {
+		t.Errorf("buyer has %d registrations, want 1", registrations)
+	}
+	var placed models.CatalogOrder
+	if err := db.Where("user_id = ?", first.ID).First(&placed).Error; err != nil {
+		t.Fatalf("load order: %v", err)
+	}
+	if placed.Status != models.OrderStatusFulfilled {
+		t.Errorf("order status is %q, want %q", placed.Status, models.OrderStatusFulfilled)
+	}
+
+	// Buying again for the same event is refused without charging
+	if code := order(first); code != http.StatusConflict {
+		t.Errorf("repeat order returned %d, want 409", code)
+	}
+	if got := balance(first); got != 70 {
+		t.Errorf("balance after repeat order is %d, want 70", got)
+	}
+
+	// The event is now full, so the next buyer is refused and keeps their points
+	second := fundedUser()
+	if code := order(second); code != http.StatusConflict {
+		t.Errorf("order for a full event returned %d, want 409", code)
+	}
+	if got := balance(second); got != 100 {
+		t.Errorf("balance after refused order is %d, want 100", got)
+	}
+	var orders int64
+	db.Model(&models.CatalogOrder{}).Where("user_id = ?", second.ID).Count(&orders)
+	if orders != 0 {
+		t.Errorf("refused order left %d orders behind", orders)
+	}
+
+	var reloaded models.CatalogItem
+	if err := db.First(&reloaded, "id = ?", item.ID).Error; err != nil {
+		t.Fatalf("reload catalog item: %v", err)
+	}
+	if reloaded.TotalOrdered != 1 {
+		t.Errorf("item total_ordered is %d, want 1", reloaded.TotalOrdered)
+	}
+}
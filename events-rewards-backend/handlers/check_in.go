@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+var (
+	errAlreadyCheckedIn = errors.New("ticket already checked in")
+	errTicketNotActive  = errors.New("registration does not hold a confirmed seat")
+)
+
+type CheckInHandler struct {
+	db      *gorm.DB
+	tickets *services.TicketTokenService
+	bus     *services.EventBus
+}
+
+func NewCheckInHandler(db *gorm.DB, tickets *services.TicketTokenService, bus *services.EventBus) *CheckInHandler {
+	return &CheckInHandler{db: db, tickets: tickets, bus: bus}
+}
+
+// ticketRegistration loads the caller's registration for the event in the
+// request path, writing an error response if they have no confirmed seat
+func (h *CheckInHandler) ticketRegistration(w http.ResponseWriter, r *http.Request) (*models.EventRegistration, bool) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	var registration models.EventRegistration
+	if err := h.db.Where("event_id = ? AND user_id = ?", mux.Vars(r)["id"], userID).First(&registration).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Registration not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch registration")
+		}
+		return nil, false
+	}
+
+	if registration.Status != models.RegistrationStatusRegistered && registration.Status != models.RegistrationStatusCheckedIn {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Tickets are only issued for confirmed registrations")
+		return nil, false
+	}
+
+	return &registration, true
+}
+
+// GetTicket - Get the signed ticket for the user's registration
+func (h *CheckInHandler) GetTicket(w http.ResponseWriter, r *http.Request) {
+	registration, ok := h.ticketRegistration(w, r)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"token":         h.tickets.Sign(registration.ID, registration.EventID),
+		"registration":  registration,
+		"checked_in":    registration.Status == models.RegistrationStatusCheckedIn,
+		"checked_in_at": registration.CheckedInAt,
+	})
+}
+
+// GetTicketQRCode - Get the user's signed ticket as a QR code PNG to show at the door
+func (h *CheckInHandler) GetTicketQRCode(w http.ResponseWriter, r *http.Request) {
+	registration, ok := h.ticketRegistration(w, r)
+	if !ok {
+		return
+	}
+
+	png, err := h.tickets.QRCode(registration.ID, registration.EventID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to generate QR code")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// CheckIn - Check in a scanned ticket at the door (event organizer or admin)
+func (h *CheckInHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	organizerID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid event ID")
+		return
+	}
+
+	var req models.CheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Token) == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Ticket token is required")
+		return
+	}
+
+	registrationID, ticketEventID, err := h.tickets.Verify(req.Token)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid ticket")
+		return
+	}
+	if ticketEventID != eventID {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Ticket is for a different event")
+		return
+	}
+
+	var event models.Event
+	if err := h.db.Where("id = ?", eventID).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Event not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event")
+		}
+		return
+	}
+	if !canManageEvent(r, &event, organizerID.String()) {
+		utils.ErrorResponse(w, http.StatusForbidden, "You can only check in attendees for your own events")
+		return
+	}
+
+	now := time.Now()
+	var registration models.EventRegistration
+
+	// Marking the ticket used is a conditional update, so scanning the same
+	// ticket at two doors at once checks it in only once
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.EventRegistration{}).
+			Where("id = ? AND event_id = ? AND status = ?", registrationID, eventID, models.RegistrationStatusRegistered).
+			Updates(map[string]interface{}{
+				"status":        models.RegistrationStatusCheckedIn,
+				"checked_in_at": now,
+				"checked_in_by": organizerID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if err := tx.Where("id = ? AND event_id = ?", registrationID, eventID).First(&registration).Error; err != nil {
+			return err
+		}
+
+		if result.RowsAffected == 0 {
+			if registration.Status == models.RegistrationStatusCheckedIn {
+				return errAlreadyCheckedIn
+			}
+			return errTicketNotActive
+		}
+
+		return tx.Model(&models.Event{}).Where("id = ?", eventID).
+			Update("checked_in_count", gorm.Expr("checked_in_count + 1")).Error
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, "Registration not found")
+		case errors.Is(err, errAlreadyCheckedIn):
+			utils.ErrorResponse(w, http.StatusConflict, "Ticket was already checked in")
+		case errors.Is(err, errTicketNotActive):
+			utils.ErrorResponse(w, http.StatusBadRequest, "Registration does not hold a confirmed seat")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to check in ticket")
+		}
+		return
+	}
+
+	h.bus.Publish(services.EventEventCheckedIn, map[string]interface{}{
+		"user_id":      registration.UserID,
+		"reference_id": eventID,
+	})
+
+	h.db.Preload("User").First(&registration, registration.ID)
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"message":      "Checked in successfully",
+		"registration": registration,
+	})
+}
+
+// GetAttendance - Get registration and check-in counts for an event (event organizer or admin)
+func (h *CheckInHandler) GetAttendance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var event models.Event
+	if err := h.db.Where("id = ?", mux.Vars(r)["id"]).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Event not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event")
+		}
+		return
+	}
+	if !canManageEvent(r, &event, userID.String()) {
+		utils.ErrorResponse(w, http.StatusForbidden, "You can only view attendance for your own events")
+		return
+	}
+
+	var counts []struct {
+		Status string
+		Count  int64
+	}
+	if err := h.db.Model(&models.EventRegistration{}).
+		Select("status, COUNT(*) AS count").
+		Where("event_id = ?", event.ID).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch attendance")
+		return
+	}
+
+	byStatus := map[string]int64{
+		models.RegistrationStatusRegistered: 0,
+		models.RegistrationStatusOffered:    0,
+		models.RegistrationStatusWaitlisted: 0,
+		models.RegistrationStatusCheckedIn:  0,
+	}
+	for _, count := range counts {
+		byStatus[count.Status] = count.Count
+	}
+
+	// The rate is taken over registrations holding a seat, which includes offers
+	// not yet confirmed, so it always compares like with like
+	seatHolders := byStatus[models.RegistrationStatusRegistered] +
+		byStatus[models.RegistrationStatusOffered] +
+		byStatus[models.RegistrationStatusCheckedIn]
+	attendanceRate := 0.0
+	if seatHolders > 0 {
+		attendanceRate = float64(byStatus[models.RegistrationStatusCheckedIn]) / float64(seatHolders) * 100
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"event_id":             event.ID,
+		"max_participants":     event.MaxParticipants,
+		"current_participants": event.CurrentParticipants,
+		"checked_in_count":     event.CheckedInCount,
+		"attendance_rate":      attendanceRate,
+		"by_status":            byStatus,
+	})
+}
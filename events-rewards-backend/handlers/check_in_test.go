@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// createTestRegistration inserts a registration for the user with the given status
+func createTestRegistration(t *testing.T, db *gorm.DB, event models.Event, user models.User, status string) models.EventRegistration {
+	t.Helper()
+
+	registration := models.EventRegistration{
+		ID:               uuid.New(),
+		UserID:           user.ID,
+		EventID:          event.ID,
+		RegistrationDate: time.Now(),
+		Status:           status,
+	}
+	if err := db.Create(&registration).Error; err != nil {
+		t.Fatalf("create registration: %v", err)
+	}
+	return registration
+}
+
+func TestCheckIn(t *testing.T) {
+	db := openTestDB(t)
+	tickets := services.NewTicketTokenService("test-secret")
+	h := NewCheckInHandler(db, tickets, services.NewEventBus())
+
+	organizer := createTestUser(t, db, nil)
+	event := createTestEvent(t, db, 10)
+	if err := db.Model(&event).Update("created_by", organizer.ID).Error; err != nil {
+		t.Fatalf("set event organizer: %v", err)
+	}
+	otherEvent := createTestEvent(t, db, 10)
+
+	attendee, offered, waitlisted := createTestUser(t, db, nil), createTestUser(t, db, nil), createTestUser(t, db, nil)
+	seat := createTestRegistration(t, db, event, attendee, models.RegistrationStatusRegistered)
+	createTestRegistration(t, db, event, createTestUser(t, db, nil), models.RegistrationStatusRegistered)
+	offer := createTestRegistration(t, db, event, offered, models.RegistrationStatusOffered)
+	queued := createTestRegistration(t, db, event, waitlisted, models.RegistrationStatusWaitlisted)
+
+	checkIn := func(scanner models.User, token string) int {
+		body := strings.NewReader(`{"token": "` + token + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/events/"+event.ID.String()+"/check-in", body)
+		req = withUser(mux.SetURLVars(req, map[string]string{"id": event.ID.String()}), scanner)
+		rec := httptest.NewRecorder()
+		h.CheckIn(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name    string
+		scanner models.User
+		token   string
+		want    int
+	}{
+		{"not the organizer", attendee, tickets.Sign(seat.ID, event.ID), http.StatusForbidden},
+		{"forged ticket", organizer, services.NewTicketTokenService("other-secret").Sign(seat.ID, event.ID), http.StatusBadRequest},
+		{"ticket for another event", organizer, tickets.Sign(seat.ID, otherEvent.ID), http.StatusBadRequest},
+		{"unconfirmed offer", organizer, tickets.Sign(offer.ID, event.ID), http.StatusBadRequest},
+		{"waitlisted", organizer, tickets.Sign(queued.ID, event.ID), http.StatusBadRequest},
+		{"confirmed seat", organizer, tickets.Sign(seat.ID, event.ID), http.StatusOK},
+		{"second scan", organizer, tickets.Sign(seat.ID, event.ID), http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if code := checkIn(tt.scanner, tt.token); code != tt.want {
+			t.Errorf("%s: check-in returned %d, want %d", tt.name, code, tt.want)
+		}
+	}
+
+	checkedIn := loadRegistration(t, db, event, attendee)
+	if checkedIn.Status != models.RegistrationStatusCheckedIn || checkedIn.CheckedInBy == nil || *checkedIn.CheckedInBy != organizer.ID {
+		t.Errorf("registration is %q checked in by %v, want checked in by the organizer", checkedIn.Status, checkedIn.CheckedInBy)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/"+event.ID.String()+"/attendance", nil)
+	req = withUser(mux.SetURLVars(req, map[string]string{"id": event.ID.String()}), organizer)
+	rec := httptest.NewRecorder()
+	h.GetAttendance(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("attendance returned %d, want 200", rec.Code)
+	}
+
+	var resp struct {
+		Data struct {
+			CheckedInCount int     `json:"checked_in_count"`
+			AttendanceRate float64 `json:"attendance_rate"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode attendance: %v", err)
+	}
+	if resp.Data.CheckedInCount != 1 {
+		t.Errorf("checked_in_count is %d, want 1", resp.Data.CheckedInCount)
+	}
+	// One of three seat holders checked in; the waitlisted user holds no seat
+	if want := 100.0 / 3; resp.Data.AttendanceRate < want-0.01 || resp.Data.AttendanceRate > want+0.01 {
+		t.Errorf("attendance_rate is %.2f, want %.2f", resp.Data.AttendanceRate, want)
+	}
+}
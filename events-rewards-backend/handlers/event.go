@@ -2,26 +2,43 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Hritikpandey-ops/events-rewards-backend/middleware"
 	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
 	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errAlreadyRegistered   = errors.New("already registered for this event")
+	errEventFull           = errors.New("event has reached maximum capacity")
+	errTicketTierNotFound  = errors.New("ticket tier not found")
+	errTicketTierNotOnSale = errors.New("ticket tier is not on sale")
+	errTicketTierSoldOut   = errors.New("ticket tier is sold out")
+	errTicketLimitExceeded = errors.New("ticket quantity exceeds the per-user limit")
 )
 
 type EventHandler struct {
-	db *gorm.DB
+	db       *gorm.DB
+	bus      *services.EventBus
+	waitlist *services.WaitlistService
 }
 
-func NewEventHandler(db *gorm.DB) *EventHandler {
-	return &EventHandler{db: db}
+func NewEventHandler(db *gorm.DB, bus *services.EventBus, waitlist *services.WaitlistService) *EventHandler {
+	return &EventHandler{db: db, bus: bus, waitlist: waitlist}
 }
 
 // GetEvents - Get all events with optional filtering
@@ -34,7 +51,7 @@ func (h *EventHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if location := r.URL.Query().Get("location"); location != "" {
-		query = query.Where("location ILIKE ?", "%"+location+"%")
+		query = query.Where("(location ILIKE ? OR venue ILIKE ?)", "%"+location+"%", "%"+location+"%")
 	}
 
 	if dateFrom := r.URL.Query().Get("date_from"); dateFrom != "" {
@@ -49,6 +66,12 @@ func (h *EventHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	geo, ok := parseGeoSearch(w, r)
+	if !ok {
+		return
+	}
+	query = geo.apply(query)
+
 	// Pagination
 	page := 1
 	limit := 10
@@ -72,10 +95,9 @@ func (h *EventHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
 	query.Count(&totalCount)
 
 	// Get events with pagination
-	result := query.
+	result := geo.order(query).
 		Preload("Creator").
 		Preload("Registrations").
-		Order("event_date ASC").
 		Offset(offset).
 		Limit(limit).
 		Find(&events)
@@ -85,6 +107,8 @@ func (h *EventHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	geo.setDistances(events)
+
 	// Calculate pagination info
 	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
 	hasNext := page < totalPages
@@ -103,6 +127,43 @@ func (h *EventHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetNearbyEvents - Get events near the user's last reported location, nearest first
+func (h *EventHandler) GetNearbyEvents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := h.db.Select("id", "location").Where("id = ?", userID).First(&user).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch user location")
+		return
+	}
+
+	// The app stores the device position reported by /user/location
+	latitude, latOK := user.Location["latitude"].(float64)
+	longitude, lngOK := user.Location["longitude"].(float64)
+	if !latOK || !lngOK {
+		utils.ErrorResponse(w, http.StatusBadRequest, "No location on file; update your location first")
+		return
+	}
+
+	// Reuse GetEvents with the stored position, keeping any other filters the caller passed
+	nearby := r.Clone(r.Context())
+	query := nearby.URL.Query()
+	query.Set("lat", strconv.FormatFloat(latitude, 'f', -1, 64))
+	query.Set("lng", strconv.FormatFloat(longitude, 'f', -1, 64))
+	if query.Get("radius_km") == "" {
+		query.Set("radius_km", strconv.FormatFloat(defaultNearbyRadiusKm, 'f', -1, 64))
+	}
+	if query.Get("sort") == "" {
+		query.Set("sort", "distance")
+	}
+	nearby.URL.RawQuery = query.Encode()
+
+	h.GetEvents(w, nearby)
+}
+
 // GetEvent - Get a specific event by ID
 func (h *EventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -118,6 +179,9 @@ func (h *EventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
 		Preload("Creator").
 		Preload("Registrations").
 		Preload("Registrations.User").
+		Preload("TicketTiers", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("sort_order, price")
+		}).
 		Where("id = ? AND is_active = ?", eventID, true).
 		First(&event)
 
@@ -264,6 +328,15 @@ func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		maxParticipants = *req.MaxParticipants
 	}
 
+	if req.WaitlistClaimHours != nil && *req.WaitlistClaimHours < 1 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Waitlist claim window must be at least 1 hour")
+		return
+	}
+
+	if req.Venue != nil && strings.TrimSpace(*req.Venue) == "" {
+		req.Venue = nil
+	}
+
 	bannerImage := ""
 	if req.BannerImage != nil {
 		bannerImage = *req.BannerImage
@@ -271,15 +344,24 @@ func (h *EventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 
 	// Create event with parsed date
 	event := models.Event{
-		Title:           req.Title,
-		Description:     &req.Description,
-		EventDate:       eventDate,
-		Location:        &req.Location,
-		MaxParticipants: &maxParticipants,
-		BannerImage:     &bannerImage,
-		Category:        &req.Category,
-		CreatedBy:       &userID,
-		IsActive:        true,
+		Title:              req.Title,
+		Description:        &req.Description,
+		EventDate:          eventDate,
+		Location:           &req.Location,
+		Venue:              req.Venue,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		MaxParticipants:    &maxParticipants,
+		BannerImage:        &bannerImage,
+		Category:           &req.Category,
+		CreatedBy:          &userID,
+		IsActive:           true,
+		WaitlistClaimHours: req.WaitlistClaimHours,
+	}
+
+	if msg := validateEventCoordinates(&event); msg != "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, msg)
+		return
 	}
 
 	fmt.Printf("DEBUG: Creating event: %+v\n", event)
@@ -332,17 +414,27 @@ func (h *EventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if user is the creator of the event
-	if event.CreatedBy == nil || event.CreatedBy.String() != userIDStr {
+	if !canManageEvent(r, &event, userIDStr) {
 		utils.ErrorResponse(w, http.StatusForbidden, "You can only update your own events")
 		return
 	}
 
-	// Update fields if provided
+	scope, ok := seriesScope(w, r, &event)
+	if !ok {
+		return
+	}
+	originalDate := event.EventDate
+
+	// Only the columns the request changes are written, so counters kept up to
+	// date by registrations are never overwritten with stale values
+	updates := map[string]interface{}{}
 	if req.Title != nil {
 		event.Title = *req.Title
+		updates["title"] = event.Title
 	}
 	if req.Description != nil {
 		event.Description = req.Description
+		updates["description"] = event.Description
 	}
 	if req.EventDate != nil {
 		if req.EventDate.Before(time.Now()) {
@@ -350,28 +442,105 @@ func (h *EventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		event.EventDate = *req.EventDate
+		updates["event_date"] = event.EventDate
 	}
 	if req.Location != nil {
 		event.Location = req.Location
+		updates["location"] = event.Location
+	}
+	if req.Venue != nil {
+		event.Venue = req.Venue
+		updates["venue"] = event.Venue
+	}
+	if req.Latitude != nil {
+		event.Latitude = req.Latitude
+		updates["latitude"] = event.Latitude
+	}
+	if req.Longitude != nil {
+		event.Longitude = req.Longitude
+		updates["longitude"] = event.Longitude
+	}
+	if msg := validateEventCoordinates(&event); msg != "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, msg)
+		return
 	}
 	if req.MaxParticipants != nil {
 		event.MaxParticipants = req.MaxParticipants
+		updates["max_participants"] = event.MaxParticipants
 	}
 	if req.BannerImage != nil {
 		event.BannerImage = req.BannerImage
+		updates["banner_image"] = event.BannerImage
 	}
 	if req.Category != nil {
 		event.Category = req.Category
+		updates["category"] = event.Category
 	}
 	if req.IsActive != nil {
 		event.IsActive = *req.IsActive
+		updates["is_active"] = event.IsActive
+	}
+	if req.WaitlistClaimHours != nil {
+		if *req.WaitlistClaimHours < 1 {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Waitlist claim window must be at least 1 hour")
+			return
+		}
+		event.WaitlistClaimHours = req.WaitlistClaimHours
+		updates["waitlist_claim_hours"] = event.WaitlistClaimHours
 	}
 
-	if result := h.db.Save(&event); result.Error != nil {
+	// Calendar clients only replace an entry when its sequence number goes up
+	updates["sequence"] = gorm.Expr("sequence + 1")
+
+	// scope=future carries the edit over to every later occurrence in the series
+	var promoted []models.EventRegistration
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Event{}).Where("id = ?", event.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		resized := []uuid.UUID{event.ID}
+		if scope == models.SeriesScopeFuture {
+			if req.MaxParticipants != nil {
+				var later []uuid.UUID
+				if err := tx.Model(&models.Event{}).
+					Where("series_id = ? AND event_date > ? AND id <> ?", *event.SeriesID, originalDate, event.ID).
+					Pluck("id", &later).Error; err != nil {
+					return err
+				}
+				resized = append(resized, later...)
+			}
+			if err := applyToFutureOccurrences(tx, &event, originalDate, &req); err != nil {
+				return err
+			}
+		}
+
+		// Seats added by raising the capacity go to the waitlist first
+		if req.MaxParticipants != nil {
+			for _, id := range resized {
+				offered, err := h.waitlist.FillOpenSeats(tx, id, time.Now())
+				if err != nil {
+					return err
+				}
+				promoted = append(promoted, offered...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update event")
 		return
 	}
 
+	for i := range promoted {
+		h.waitlist.AnnouncePromotion(&promoted[i])
+	}
+
+	if err := h.db.Where("id = ?", event.ID).First(&event).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event")
+		return
+	}
+
 	utils.SuccessResponse(w, event)
 }
 
@@ -403,13 +572,27 @@ func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if user is the creator of the event
-	if event.CreatedBy.String() != userID {
+	if !canManageEvent(r, &event, userID) {
 		utils.ErrorResponse(w, http.StatusForbidden, "You can only delete your own events")
 		return
 	}
 
-	// Soft delete the event (sets deleted_at timestamp)
-	if result := h.db.Delete(&event); result.Error != nil {
+	scope, ok := seriesScope(w, r, &event)
+	if !ok {
+		return
+	}
+
+	// Soft delete the event (sets deleted_at timestamp). Occurrences of a series
+	// are also recorded on the series, and scope=future ends the series here.
+	if event.SeriesID != nil {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			return deleteFromSeries(tx, &event, scope)
+		})
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to delete event")
+			return
+		}
+	} else if result := h.db.Delete(&event); result.Error != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to delete event")
 		return
 	}
@@ -417,6 +600,14 @@ func (h *EventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	utils.MessageResponse(w, "Event deleted successfully")
 }
 
+// canManageEvent reports whether the user created the event or is an admin
+func canManageEvent(r *http.Request, event *models.Event, userID string) bool {
+	if middleware.IsAdmin(r) {
+		return true
+	}
+	return event.CreatedBy != nil && event.CreatedBy.String() == userID
+}
+
 // RegisterForEvent - Register a user for an event
 func (h *EventHandler) RegisterForEvent(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -440,6 +631,22 @@ func (h *EventHandler) RegisterForEvent(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// The body is optional; events without ticket tiers take a single seat
+	var req models.RegisterForEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Quantity must be at least 1")
+		return
+	}
+
 	// Check if event exists and is active
 	var event models.Event
 	result := h.db.Where("id = ? AND is_active = ?", eventID, true).First(&event)
@@ -465,43 +672,181 @@ func (h *EventHandler) RegisterForEvent(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Check if event has reached maximum capacity
-	if event.MaxParticipants != nil && event.CurrentParticipants >= *event.MaxParticipants {
-		utils.ErrorResponse(w, http.StatusBadRequest, "Event has reached maximum capacity")
+	// Events with ticket tiers must be booked through one of them
+	var tierCount int64
+	if err := h.db.Model(&models.TicketTier{}).Where("event_id = ?", event.ID).Count(&tierCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch ticket tiers")
 		return
 	}
 
-	// Create registration
-	registration := models.EventRegistration{
-		UserID:  userID,
-		EventID: uuid.MustParse(eventID),
-		Status:  "registered",
+	var tierID *uuid.UUID
+	switch {
+	case tierCount > 0 && req.TicketTierID == "":
+		utils.ErrorResponse(w, http.StatusBadRequest, "A ticket tier is required for this event")
+		return
+	case tierCount == 0 && req.TicketTierID != "":
+		utils.ErrorResponse(w, http.StatusBadRequest, "This event has no ticket tiers")
+		return
+	case tierCount > 0:
+		parsed, err := uuid.Parse(req.TicketTierID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid ticket tier ID")
+			return
+		}
+		tierID = &parsed
+	case quantity != 1:
+		utils.ErrorResponse(w, http.StatusBadRequest, "Only one seat can be booked for this event")
+		return
 	}
 
-	tx := h.db.Begin()
+	now := time.Now()
+	registration := models.EventRegistration{
+		ID:               uuid.New(),
+		UserID:           userID,
+		EventID:          event.ID,
+		RegistrationDate: now,
+		Status:           models.RegistrationStatusRegistered,
+		TicketTierID:     tierID,
+		Quantity:         quantity,
+	}
+
+	// Taking the seat and recording the registration are one transaction, so a
+	// duplicate or retried request rolls its seat back instead of double-booking
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if tierID != nil {
+			// Tiered tickets are sold, not queued, so a full event is simply sold out
+			if err := h.takeTierTickets(tx, event.ID, *tierID, quantity, now); err != nil {
+				return err
+			}
+			seated, err := takeSeat(tx, event.ID, quantity)
+			if err != nil {
+				return err
+			}
+			if !seated {
+				return errEventFull
+			}
+		} else {
+			seated, err := takeSeat(tx, event.ID, 1)
+			if err != nil {
+				return err
+			}
+			if !seated {
+				// A full event puts the user on its waitlist instead of turning them away
+				registration.Status = models.RegistrationStatusWaitlisted
+			}
+		}
 
-	if result := tx.Create(&registration); result.Error != nil {
-		tx.Rollback()
-		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to register for event")
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&registration)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyRegistered
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errAlreadyRegistered):
+			utils.ErrorResponse(w, http.StatusConflict, "Already registered for this event")
+		case errors.Is(err, errTicketTierNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, "Ticket tier not found")
+		case errors.Is(err, errTicketTierNotOnSale):
+			utils.ErrorResponse(w, http.StatusBadRequest, "Ticket tier is not on sale")
+		case errors.Is(err, errTicketLimitExceeded):
+			utils.ErrorResponse(w, http.StatusBadRequest, "Quantity exceeds the per-user limit for this ticket tier")
+		case errors.Is(err, errTicketTierSoldOut):
+			utils.ErrorResponse(w, http.StatusConflict, "Ticket tier is sold out")
+		case errors.Is(err, errEventFull):
+			utils.ErrorResponse(w, http.StatusConflict, "Event has reached maximum capacity")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to register for event")
+		}
 		return
 	}
 
-	// Update current participants count
-	if result := tx.Model(&event).Update("current_participants", gorm.Expr("current_participants + 1")); result.Error != nil {
-		tx.Rollback()
-		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update participant count")
+	if registration.Status == models.RegistrationStatusWaitlisted {
+		position, err := h.waitlist.Position(&registration)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch waitlist position")
+			return
+		}
+
+		utils.SuccessResponse(w, map[string]interface{}{
+			"message":           "Event is full, you have been added to the waitlist",
+			"registration":      registration,
+			"waitlist_position": position,
+		})
 		return
 	}
 
-	tx.Commit()
+	h.bus.Publish(services.EventEventRegistered, map[string]interface{}{
+		"user_id":      userID,
+		"reference_id": event.ID,
+	})
 
 	// Load registration with relationships
-	h.db.Preload("User").Preload("Event").First(&registration, registration.ID)
+	h.db.Preload("User").Preload("Event").Preload("TicketTier").First(&registration, registration.ID)
 
-	utils.SuccessResponse(w, map[string]interface{}{
+	response := map[string]interface{}{
 		"message":      "Successfully registered for event",
 		"registration": registration,
-	})
+	}
+	if registration.TicketTier != nil {
+		response["total_price"] = registration.TicketTier.Price * float64(registration.Quantity)
+	}
+
+	utils.SuccessResponse(w, response)
+}
+
+// takeSeat claims seats on the event if enough are left. The capacity check
+// and increment are a single conditional update, so concurrent claims cannot
+// overbook. If the event looks full it checks again under the event row lock
+// that WaitlistService.ReleaseSeat also takes, so a seat freed in the meantime
+// is taken rather than the user being waitlisted behind nobody.
+func takeSeat(tx *gorm.DB, eventID uuid.UUID, seats int) (bool, error) {
+	claim := func() (bool, error) {
+		result := tx.Model(&models.Event{}).
+			Where("id = ? AND (max_participants IS NULL OR current_participants + ? <= max_participants)", eventID, seats).
+			Update("current_participants", gorm.Expr("current_participants + ?", seats))
+		return result.RowsAffected == 1, result.Error
+	}
+
+	seated, err := claim()
+	if err != nil || seated {
+		return seated, err
+	}
+
+	if err := tx.Exec("SELECT 1 FROM events WHERE id = ? FOR UPDATE", eventID).Error; err != nil {
+		return false, err
+	}
+	return claim()
+}
+
+// takeTierTickets sells quantity tickets from a tier, enforcing its sale
+// window, per-user limit and capacity under the tier's row lock
+func (h *EventHandler) takeTierTickets(tx *gorm.DB, eventID, tierID uuid.UUID, quantity int, now time.Time) error {
+	var tier models.TicketTier
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND event_id = ?", tierID, eventID).
+		First(&tier).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errTicketTierNotFound
+		}
+		return err
+	}
+
+	if !tier.OnSale(now) {
+		return errTicketTierNotOnSale
+	}
+	if quantity > tier.PerUserLimit {
+		return errTicketLimitExceeded
+	}
+	if remaining := tier.Remaining(); remaining != nil && *remaining < quantity {
+		return errTicketTierSoldOut
+	}
+
+	return tx.Model(&tier).Update("sold", gorm.Expr("sold + ?", quantity)).Error
 }
 
 // UnregisterFromEvent - Unregister a user from an event
@@ -539,6 +884,11 @@ func (h *EventHandler) UnregisterFromEvent(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if registration.Status == models.RegistrationStatusCheckedIn {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Cannot unregister after checking in")
+		return
+	}
+
 	tx := h.db.Begin()
 
 	// Delete registration
@@ -548,18 +898,89 @@ func (h *EventHandler) UnregisterFromEvent(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Update current participants count
-	if result := tx.Model(&models.Event{}).Where("id = ?", eventID).Update("current_participants", gorm.Expr("current_participants - 1")); result.Error != nil {
+	// A freed seat goes to the next person on the waitlist in the same
+	// transaction; tiered tickets go back on sale instead
+	var promoted *models.EventRegistration
+	switch {
+	case registration.TicketTierID != nil:
+		err = releaseTierTickets(tx, &registration)
+	case registration.Status != models.RegistrationStatusWaitlisted:
+		promoted, err = h.waitlist.ReleaseSeat(tx, registration.EventID, time.Now())
+	}
+	if err != nil {
 		tx.Rollback()
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update participant count")
 		return
 	}
 
-	tx.Commit()
+	if err := tx.Commit().Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to unregister from event")
+		return
+	}
+
+	h.waitlist.AnnouncePromotion(promoted)
 
 	utils.MessageResponse(w, "Successfully unregistered from event")
 }
 
+// releaseTierTickets returns a tiered registration's tickets to its tier and frees its seats
+func releaseTierTickets(tx *gorm.DB, registration *models.EventRegistration) error {
+	if err := tx.Model(&models.TicketTier{}).
+		Where("id = ?", *registration.TicketTierID).
+		Update("sold", gorm.Expr("GREATEST(sold - ?, 0)", registration.Quantity)).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Event{}).
+		Where("id = ?", registration.EventID).
+		Update("current_participants", gorm.Expr("GREATEST(current_participants - ?, 0)", registration.Quantity)).Error
+}
+
+// ConfirmWaitlistOffer - Accept the seat offered after being promoted from the waitlist
+func (h *EventHandler) ConfirmWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid event ID")
+		return
+	}
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// The claim window is checked in the update itself so an offer cannot be
+	// confirmed after the expiry worker has started to withdraw it
+	result := h.db.Model(&models.EventRegistration{}).
+		Where("user_id = ? AND event_id = ? AND status = ? AND claim_expires_at > ?",
+			userID, eventID, models.RegistrationStatusOffered, time.Now()).
+		Updates(map[string]interface{}{
+			"status":           models.RegistrationStatusRegistered,
+			"claim_expires_at": nil,
+		})
+	if result.Error != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to confirm seat")
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.ErrorResponse(w, http.StatusConflict, "No seat offer to confirm, or the offer has expired")
+		return
+	}
+
+	h.bus.Publish(services.EventEventRegistered, map[string]interface{}{
+		"user_id":      userID,
+		"reference_id": eventID,
+	})
+
+	var registration models.EventRegistration
+	h.db.Preload("Event").Where("user_id = ? AND event_id = ?", userID, eventID).First(&registration)
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"message":      "Seat confirmed",
+		"registration": registration,
+	})
+}
+
 // GetUserRegistrations - Get all events a user is registered for
 func (h *EventHandler) GetUserRegistrations(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
@@ -569,18 +990,23 @@ func (h *EventHandler) GetUserRegistrations(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	var registrations []models.EventRegistration
-	result := h.db.
-		Preload("Event").
-		Preload("Event.Creator").
-		Where("user_id = ?", userIDStr).
-		Order("registration_date DESC").
-		Find(&registrations)
-
-	if result.Error != nil {
+	registrations, err := loadUserRegistrations(h.db, userIDStr)
+	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch registrations")
 		return
 	}
 
 	utils.SuccessResponse(w, registrations)
 }
+
+// loadUserRegistrations returns a user's registrations with their events, newest first
+func loadUserRegistrations(db *gorm.DB, userID string) ([]models.EventRegistration, error) {
+	var registrations []models.EventRegistration
+	err := db.
+		Preload("Event").
+		Preload("Event.Creator").
+		Where("user_id = ?", userID).
+		Order("registration_date DESC").
+		Find(&registrations).Error
+	return registrations, err
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventSeriesHandler struct {
+	db *gorm.DB
+}
+
+func NewEventSeriesHandler(db *gorm.DB) *EventSeriesHandler {
+	return &EventSeriesHandler{db: db}
+}
+
+// validateEventSeries checks a series' recurrence rule, returning a message describing the first problem
+func validateEventSeries(series *models.EventSeries) string {
+	switch {
+	case strings.TrimSpace(series.Title) == "":
+		return "Title is required"
+	case series.Frequency != models.RecurrenceDaily && series.Frequency != models.RecurrenceWeekly && series.Frequency != models.RecurrenceMonthly:
+		return "Frequency must be daily, weekly or monthly"
+	case series.Interval < 1:
+		return "Interval must be at least 1"
+	case series.Until == nil && series.Count == nil:
+		return "Either until or count is required"
+	case series.Count != nil && *series.Count < 1:
+		return "Count must be at least 1"
+	case series.Until != nil && series.Until.Before(series.StartsAt):
+		return "Until must be after the first occurrence"
+	case series.StartsAt.Before(time.Now()):
+		return "The first occurrence cannot be in the past"
+	case len(series.ByWeekday) > 0 && series.Frequency != models.RecurrenceWeekly:
+		return "by_weekday is only supported for weekly series"
+	}
+
+	if _, err := time.LoadLocation(series.Timezone); err != nil {
+		return "Invalid timezone"
+	}
+	for _, code := range series.ByWeekday {
+		if _, ok := services.RecurrenceWeekdays[code]; !ok {
+			return "Invalid weekday " + code + "; use MO, TU, WE, TH, FR, SA or SU"
+		}
+	}
+	for _, date := range series.Exceptions {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return "Exceptions must be dates in YYYY-MM-DD format"
+		}
+	}
+
+	return ""
+}
+
+// uniqueStrings returns values with surrounding space and duplicates removed, upper-cased if upper is set
+func uniqueStrings(values []string, upper bool) models.StringList {
+	seen := make(map[string]bool, len(values))
+	var unique models.StringList
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if upper {
+			value = strings.ToUpper(value)
+		}
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
+
+// CreateSeries - Create a recurring event series and its occurrences (organizers and admins)
+func (h *EventSeriesHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateEventSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Match CreateEvent's default capacity so series occurrences behave like single events
+	maxParticipants := 50
+	if req.MaxParticipants != nil {
+		maxParticipants = *req.MaxParticipants
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	series := models.EventSeries{
+		ID:              uuid.New(),
+		Title:           strings.TrimSpace(req.Title),
+		Description:     req.Description,
+		Location:        req.Location,
+		MaxParticipants: &maxParticipants,
+		BannerImage:     req.BannerImage,
+		Category:        req.Category,
+		StartsAt:        req.StartsAt,
+		Timezone:        timezone,
+		Frequency:       strings.ToLower(req.Frequency),
+		Interval:        interval,
+		ByWeekday:       uniqueStrings(req.ByWeekday, true),
+		Until:           req.Until,
+		Count:           req.Count,
+		Exceptions:      uniqueStrings(req.Exceptions, false),
+		CreatedBy:       &userID,
+	}
+
+	if msg := validateEventSeries(&series); msg != "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	occurrences, err := services.ExpandRecurrence(&series)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyOccurrences) {
+			utils.ErrorResponse(w, http.StatusBadRequest, "A series can have at most 200 occurrences")
+		} else {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid recurrence rule")
+		}
+		return
+	}
+	if len(occurrences) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Recurrence rule produces no occurrences")
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+
+		events := make([]models.Event, 0, len(occurrences))
+		for i := range occurrences {
+			occurrenceStart := occurrences[i].UTC()
+			events = append(events, models.Event{
+				Title:           series.Title,
+				Description:     series.Description,
+				EventDate:       occurrenceStart,
+				Location:        series.Location,
+				MaxParticipants: series.MaxParticipants,
+				BannerImage:     series.BannerImage,
+				Category:        series.Category,
+				IsActive:        true,
+				CreatedBy:       &userID,
+				SeriesID:        &series.ID,
+				OccurrenceStart: &occurrenceStart,
+			})
+		}
+		return tx.Create(&events).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create event series")
+		return
+	}
+
+	h.db.Preload("Occurrences", func(db *gorm.DB) *gorm.DB {
+		return db.Order("event_date")
+	}).First(&series, series.ID)
+
+	utils.SuccessResponse(w, series)
+}
+
+// GetSeries - Get an event series with its upcoming occurrences
+func (h *EventSeriesHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	var series models.EventSeries
+	err := h.db.
+		Preload("Occurrences", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ? AND event_date >= ?", true, time.Now()).Order("event_date")
+		}).
+		Where("id = ?", mux.Vars(r)["id"]).
+		First(&series).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Event series not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event series")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, series)
+}
+
+// seriesScope reads the scope query parameter used when editing or deleting an
+// occurrence, writing an error response if it is invalid for the event
+func seriesScope(w http.ResponseWriter, r *http.Request, event *models.Event) (string, bool) {
+	scope := r.URL.Query().Get("scope")
+	switch scope {
+	case "", models.SeriesScopeThis:
+		return models.SeriesScopeThis, true
+	case models.SeriesScopeFuture:
+		if event.SeriesID == nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Only events in a series can use scope=future")
+			return "", false
+		}
+		return scope, true
+	default:
+		utils.ErrorResponse(w, http.StatusBadRequest, "Scope must be this or future")
+		return "", false
+	}
+}
+
+// applyToFutureOccurrences copies the fields set in req to every later
+// occurrence in the event's series and to the series itself. Later start times
+// move by the same amount the edited occurrence moved.
+func applyToFutureOccurrences(tx *gorm.DB, event *models.Event, originalDate time.Time, req *models.UpdateEventRequest) error {
+	updates := map[string]interface{}{}
+	seriesUpdates := map[string]interface{}{}
+
+	if req.Title != nil {
+		updates["title"] = *req.Title
+		seriesUpdates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+		seriesUpdates["description"] = *req.Description
+	}
+	if req.Location != nil {
+		updates["location"] = *req.Location
+		seriesUpdates["location"] = *req.Location
+	}
+	if req.Venue != nil {
+		updates["venue"] = *req.Venue
+	}
+	if req.Latitude != nil {
+		updates["latitude"] = *req.Latitude
+	}
+	if req.Longitude != nil {
+		updates["longitude"] = *req.Longitude
+	}
+	if req.MaxParticipants != nil {
+		updates["max_participants"] = *req.MaxParticipants
+		seriesUpdates["max_participants"] = *req.MaxParticipants
+	}
+	if req.BannerImage != nil {
+		updates["banner_image"] = *req.BannerImage
+		seriesUpdates["banner_image"] = *req.BannerImage
+	}
+	if req.Category != nil {
+		updates["category"] = *req.Category
+		seriesUpdates["category"] = *req.Category
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.WaitlistClaimHours != nil {
+		updates["waitlist_claim_hours"] = *req.WaitlistClaimHours
+	}
+	if shift := event.EventDate.Sub(originalDate); shift != 0 {
+		updates["event_date"] = gorm.Expr("event_date + make_interval(secs => ?)", shift.Seconds())
+	}
+
+	if len(updates) > 0 {
+		updates["sequence"] = gorm.Expr("sequence + 1")
+		if err := tx.Model(&models.Event{}).
+			Where("series_id = ? AND event_date > ? AND id <> ?", *event.SeriesID, originalDate, event.ID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	if len(seriesUpdates) > 0 {
+		if err := tx.Model(&models.EventSeries{}).Where("id = ?", *event.SeriesID).Updates(seriesUpdates).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteFromSeries removes an occurrence, or it and every later occurrence,
+// and records the change on the series so its rule still describes the events
+func deleteFromSeries(tx *gorm.DB, event *models.Event, scope string) error {
+	var series models.EventSeries
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *event.SeriesID).First(&series).Error; err != nil {
+		return err
+	}
+
+	occurrenceStart := event.EventDate
+	if event.OccurrenceStart != nil {
+		occurrenceStart = *event.OccurrenceStart
+	}
+
+	if scope == models.SeriesScopeFuture {
+		if err := tx.Where("series_id = ? AND event_date >= ?", series.ID, event.EventDate).Delete(&models.Event{}).Error; err != nil {
+			return err
+		}
+		// End the series just before this occurrence
+		until := occurrenceStart.Add(-time.Second)
+		return tx.Model(&series).Update("until", until).Error
+	}
+
+	if err := tx.Delete(event).Error; err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(series.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	exception := occurrenceStart.In(loc).Format("2006-01-02")
+	if series.Exceptions.Contains(exception) {
+		return nil
+	}
+	return tx.Model(&series).Update("exceptions", append(series.Exceptions, exception)).Error
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+)
+
+func TestRegisterForEventCapacityUnderConcurrency(t *testing.T) {
+	db := openTestDB(t)
+
+	const capacity = 5
+	const attempts = 20
+
+	event := createTestEvent(t, db, capacity)
+	t.Cleanup(func() {
+		db.Model(&models.Event{}).Where("id = ?", event.ID).Update("is_active", false)
+	})
+
+	users := make([]models.User, attempts)
+	for i := range users {
+		users[i] = createTestUser(t, db, nil)
+	}
+
+	bus := services.NewEventBus()
+	h := NewEventHandler(db, bus, services.NewWaitlistService(db, bus))
+
+	codes := make(chan int, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, user := range users {
+		wg.Add(1)
+		go func(user models.User) {
+			defer wg.Done()
+			<-start
+			rec := httptest.NewRecorder()
+			h.RegisterForEvent(rec, eventRequest(http.MethodPost, event, user))
+			codes <- rec.Code
+		}(user)
+	}
+	close(start)
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+
+	var stored models.Event
+	if err := db.Where("id = ?", event.ID).First(&stored).Error; err != nil {
+		t.Fatalf("reload event: %v", err)
+	}
+	if stored.CurrentParticipants != capacity {
+		t.Errorf("current_participants = %d, want %d", stored.CurrentParticipants, capacity)
+	}
+
+	var registered, waitlisted int64
+	if err := db.Model(&models.EventRegistration{}).
+		Where("event_id = ? AND status = ?", event.ID, models.RegistrationStatusRegistered).
+		Count(&registered).Error; err != nil {
+		t.Fatalf("count registrations: %v", err)
+	}
+	if err := db.Model(&models.EventRegistration{}).
+		Where("event_id = ? AND status = ?", event.ID, models.RegistrationStatusWaitlisted).
+		Count(&waitlisted).Error; err != nil {
+		t.Fatalf("count waitlisted registrations: %v", err)
+	}
+	if registered != capacity {
+		t.Errorf("%d registrations hold a seat, want %d", registered, capacity)
+	}
+	if waitlisted != attempts-capacity {
+		t.Errorf("%d registrations were waitlisted, want %d", waitlisted, attempts-capacity)
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/middleware"
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type FairnessHandler struct {
+	db    *gorm.DB
+	seeds *services.SpinSeedService
+}
+
+func NewFairnessHandler(db *gorm.DB, seeds *services.SpinSeedService) *FairnessHandler {
+	return &FairnessHandler{db: db, seeds: seeds}
+}
+
+// GetCurrentSeed - Get the published hash of the seed used for spins in the current period
+func (h *FairnessHandler) GetCurrentSeed(w http.ResponseWriter, r *http.Request) {
+	seed, err := h.seeds.CurrentSeed(h.db, time.Now())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch current seed")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"seed_id":      seed.ID,
+		"seed_hash":    seed.SeedHash,
+		"period_start": seed.PeriodStart,
+		"period_end":   seed.PeriodEnd,
+	})
+}
+
+// ListRevealedSeeds - Get the seeds of finished periods alongside their published hashes
+func (h *FairnessHandler) ListRevealedSeeds(w http.ResponseWriter, r *http.Request) {
+	limit := 30
+	if value, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && value > 0 && value <= 100 {
+		limit = value
+	}
+
+	var seeds []models.SpinSeed
+	if err := h.db.Where("revealed_at IS NOT NULL").Order("period_start DESC").Limit(limit).Find(&seeds).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch seeds")
+		return
+	}
+
+	responseSeeds := make([]map[string]interface{}, 0, len(seeds))
+	for _, seed := range seeds {
+		responseSeeds = append(responseSeeds, revealedSeedResponse(&seed))
+	}
+
+	utils.SuccessResponse(w, responseSeeds)
+}
+
+// GetSpinProof - Get the inputs needed to verify one of the user's spins
+func (h *FairnessHandler) GetSpinProof(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	proof, err := h.loadProof(mux.Vars(r)["id"])
+	if err != nil || (proof.UserID != userID && !middleware.IsAdmin(r)) {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, "Spin not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin")
+		}
+		return
+	}
+
+	response := map[string]interface{}{
+		"spin_id":      proof.ID,
+		"user_id":      proof.UserID,
+		"nonce":        proof.Nonce,
+		"random_value": proof.RandomValue,
+		"pool":         proof.Pool,
+		"reward_id":    proof.RewardID,
+		"campaign_id":  proof.CampaignID,
+		"created_at":   proof.CreatedAt,
+		"seed":         revealedSeedResponse(&proof.Seed),
+	}
+
+	utils.SuccessResponse(w, response)
+}
+
+// VerifySpin - Reproduce a spin from its revealed seed, either by spin ID or from raw inputs
+func (h *FairnessHandler) VerifySpin(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifySpinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.SpinID != "" {
+		proof, err := h.loadProof(req.SpinID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				utils.ErrorResponse(w, http.StatusNotFound, "Spin not found")
+			} else {
+				utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin")
+			}
+			return
+		}
+		if !proof.Seed.IsRevealed() {
+			utils.ErrorResponse(w, http.StatusConflict, "The seed for this spin is revealed after "+proof.Seed.PeriodEnd.Format(time.RFC3339))
+			return
+		}
+
+		req.Seed = proof.Seed.Seed
+		req.SeedHash = proof.Seed.SeedHash
+		req.UserID = proof.UserID.String()
+		req.Nonce = proof.Nonce
+		req.Pool = proof.Pool
+		req.RewardID = proof.RewardID.String()
+	}
+
+	if req.Seed == "" || req.Nonce < 1 || len(req.Pool) == 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Provide a spin_id, or seed, user_id, nonce, pool and reward_id")
+		return
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	rewardID, err := uuid.Parse(req.RewardID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid reward ID")
+		return
+	}
+
+	result, err := services.VerifySpin(req.Seed, req.SeedHash, userID, req.Nonce, req.Pool, rewardID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"verified":           result.Matches,
+		"random_value":       result.RandomValue,
+		"expected_reward_id": result.ExpectedRewardID,
+		"reward_id":          rewardID,
+		"seed":               req.Seed,
+		"seed_hash":          services.HashSpinSeed(req.Seed),
+		"nonce":              req.Nonce,
+		"pool":               req.Pool,
+	}
+	// This endpoint is public, so a spin looked up by ID must not reveal whose it is
+	if req.SpinID == "" {
+		response["user_id"] = userID
+	}
+
+	utils.SuccessResponse(w, response)
+}
+
+// loadProof fetches a spin proof with its seed
+func (h *FairnessHandler) loadProof(id string) (*models.SpinProof, error) {
+	var proof models.SpinProof
+	if err := h.db.Preload("Seed").Where("id = ?", id).First(&proof).Error; err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// revealedSeedResponse describes a seed, including the seed itself only once it has been revealed
+func revealedSeedResponse(seed *models.SpinSeed) map[string]interface{} {
+	response := map[string]interface{}{
+		"seed_id":      seed.ID,
+		"seed_hash":    seed.SeedHash,
+		"period_start": seed.PeriodStart,
+		"period_end":   seed.PeriodEnd,
+		"revealed_at":  seed.RevealedAt,
+		"seed":         nil,
+	}
+	if seed.IsRevealed() {
+		response["seed"] = seed.Seed
+	}
+	return response
+}
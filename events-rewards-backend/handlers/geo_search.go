@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultNearbyRadiusKm is the radius used by the nearby events endpoint when none is given
+	defaultNearbyRadiusKm = 25.0
+	maxSearchRadiusKm     = 1000.0
+)
+
+// eventDistanceSQL is the haversine distance in kilometres from an event to a
+// point. Its arguments are the Earth's radius, the point's latitude twice and
+// its longitude. least() guards asin against rounding just above 1.
+const eventDistanceSQL = "(2 * ? * asin(least(1, sqrt(" +
+	"power(sin(radians(latitude - ?) / 2), 2) + " +
+	"cos(radians(?)) * cos(radians(latitude)) * power(sin(radians(longitude - ?) / 2), 2)))))"
+
+// geoSearch is a location filter read from the query string
+type geoSearch struct {
+	origin         *services.GeoPoint
+	radiusKm       float64
+	box            *services.GeoBox
+	sortByDistance bool
+}
+
+// parseFloatParam reads an optional float query parameter, reporting whether it was present and valid
+func parseFloatParam(r *http.Request, name string) (float64, bool, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, false, true
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, true, false
+	}
+	return value, true, true
+}
+
+// parseGeoSearch reads the lat/lng/radius_km, min_lat/max_lat/min_lng/max_lng
+// and sort=distance query parameters, writing an error response if they are invalid
+func parseGeoSearch(w http.ResponseWriter, r *http.Request) (*geoSearch, bool) {
+	search := &geoSearch{}
+
+	lat, hasLat, latOK := parseFloatParam(r, "lat")
+	lng, hasLng, lngOK := parseFloatParam(r, "lng")
+	if !latOK || !lngOK || hasLat != hasLng || (hasLat && !services.ValidCoordinates(lat, lng)) {
+		utils.ErrorResponse(w, http.StatusBadRequest, "lat and lng must be given together as valid coordinates")
+		return nil, false
+	}
+	if hasLat {
+		search.origin = &services.GeoPoint{Latitude: lat, Longitude: lng}
+	}
+
+	radius, hasRadius, radiusOK := parseFloatParam(r, "radius_km")
+	if !radiusOK || (hasRadius && (radius <= 0 || radius > maxSearchRadiusKm)) {
+		utils.ErrorResponse(w, http.StatusBadRequest, "radius_km must be greater than 0 and at most 1000")
+		return nil, false
+	}
+	if hasRadius {
+		if search.origin == nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "radius_km requires lat and lng")
+			return nil, false
+		}
+		search.radiusKm = radius
+	}
+
+	minLat, hasMinLat, minLatOK := parseFloatParam(r, "min_lat")
+	maxLat, hasMaxLat, maxLatOK := parseFloatParam(r, "max_lat")
+	minLng, hasMinLng, minLngOK := parseFloatParam(r, "min_lng")
+	maxLng, hasMaxLng, maxLngOK := parseFloatParam(r, "max_lng")
+	if hasMinLat || hasMaxLat || hasMinLng || hasMaxLng {
+		if !minLatOK || !maxLatOK || !minLngOK || !maxLngOK || !hasMinLat || !hasMaxLat || !hasMinLng || !hasMaxLng ||
+			!services.ValidCoordinates(minLat, minLng) || !services.ValidCoordinates(maxLat, maxLng) || minLat > maxLat {
+			utils.ErrorResponse(w, http.StatusBadRequest, "min_lat, max_lat, min_lng and max_lng must all be given as a valid bounding box")
+			return nil, false
+		}
+		// min_lng greater than max_lng describes a box that crosses the antimeridian
+		search.box = &services.GeoBox{MinLatitude: minLat, MaxLatitude: maxLat, MinLongitude: minLng, MaxLongitude: maxLng}
+	}
+
+	if r.URL.Query().Get("sort") == "distance" {
+		if search.origin == nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "sort=distance requires lat and lng")
+			return nil, false
+		}
+		search.sortByDistance = true
+	}
+
+	return search, true
+}
+
+// whereInBox restricts query to events inside box
+func whereInBox(query *gorm.DB, box services.GeoBox) *gorm.DB {
+	query = query.Where("latitude BETWEEN ? AND ?", box.MinLatitude, box.MaxLatitude)
+	if box.MinLongitude <= box.MaxLongitude {
+		return query.Where("longitude BETWEEN ? AND ?", box.MinLongitude, box.MaxLongitude)
+	}
+	return query.Where("(longitude >= ? OR longitude <= ?)", box.MinLongitude, box.MaxLongitude)
+}
+
+// apply adds the search's filters to an events query. Events without
+// coordinates never match a radius or bounding box.
+func (s *geoSearch) apply(query *gorm.DB) *gorm.DB {
+	if s.box != nil {
+		query = whereInBox(query, *s.box)
+	}
+	if s.radiusKm > 0 {
+		// The bounding box lets the coordinates index narrow the rows before the exact distance check
+		query = whereInBox(query, services.BoundingBox(*s.origin, s.radiusKm))
+		query = query.Where(eventDistanceSQL+" <= ?",
+			services.EarthRadiusKm, s.origin.Latitude, s.origin.Latitude, s.origin.Longitude, s.radiusKm)
+	}
+	return query
+}
+
+// order sorts an events query by distance when requested, placing events without coordinates last
+func (s *geoSearch) order(query *gorm.DB) *gorm.DB {
+	if !s.sortByDistance {
+		return query.Order("event_date ASC")
+	}
+	return query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  eventDistanceSQL + " ASC NULLS LAST, event_date ASC",
+		Vars: []interface{}{services.EarthRadiusKm, s.origin.Latitude, s.origin.Latitude, s.origin.Longitude},
+	}})
+}
+
+// setDistances fills in each event's distance from the search origin
+func (s *geoSearch) setDistances(events []models.Event) {
+	if s.origin == nil {
+		return
+	}
+	for i := range events {
+		if events[i].Latitude == nil || events[i].Longitude == nil {
+			continue
+		}
+		distance := services.DistanceKm(*s.origin, services.GeoPoint{Latitude: *events[i].Latitude, Longitude: *events[i].Longitude})
+		events[i].DistanceKm = &distance
+	}
+}
+
+// validateEventCoordinates checks that an event has both or neither of latitude and longitude, and that they are in range
+func validateEventCoordinates(event *models.Event) string {
+	if (event.Latitude == nil) != (event.Longitude == nil) {
+		return "Latitude and longitude must be set together"
+	}
+	if event.Latitude != nil && !services.ValidCoordinates(*event.Latitude, *event.Longitude) {
+		return "Latitude must be between -90 and 90 and longitude between -180 and 180"
+	}
+	return ""
+}
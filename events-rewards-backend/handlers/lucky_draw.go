@@ -4,24 +4,60 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
 	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	errSpinLimitReached       = errors.New("spin limit reached")
+	errWeeklySpinLimitReached = errors.New("weekly spin limit reached")
+	errNoRewardsAvailable     = errors.New("no rewards available")
+	errCampaignNotRunning     = errors.New("campaign not running")
+	errCampaignLimitReached   = errors.New("campaign spin limit reached")
+)
+
+// errCampaignIneligible is returned when the user does not meet a campaign's eligibility rules
+type errCampaignIneligible struct {
+	reason string
+}
+
+func (e errCampaignIneligible) Error() string {
+	return e.reason
+}
+
+// errSpinCooldown is returned when the user spun too recently under the policy's cooldown
+type errSpinCooldown struct {
+	nextSpinAt time.Time
+}
+
+func (e errSpinCooldown) Error() string {
+	return "spin cooldown active until " + e.nextSpinAt.Format(time.RFC3339)
+}
+
 type LuckyDrawHandler struct {
-	db *gorm.DB
+	db             *gorm.DB
+	seeds          *services.SpinSeedService
+	points         *services.PointsLedger
+	bonus          *services.BonusSpinService
+	trustedProxies []*net.IPNet
 }
 
-func NewLuckyDrawHandler(db *gorm.DB) *LuckyDrawHandler {
-	return &LuckyDrawHandler{db: db}
+func NewLuckyDrawHandler(db *gorm.DB, seeds *services.SpinSeedService, points *services.PointsLedger, bonus *services.BonusSpinService, trustedProxies []*net.IPNet) *LuckyDrawHandler {
+	return &LuckyDrawHandler{db: db, seeds: seeds, points: points, bonus: bonus, trustedProxies: trustedProxies}
 }
 
 // Spin - Perform a lucky draw spin
@@ -39,129 +75,542 @@ func (h *LuckyDrawHandler) Spin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	location, _ := time.LoadLocation("Asia/Kolkata")
-	now := time.Now().In(location)
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-
-	// Check daily spin limits (3 spins per day)
-	var spinAttempt models.SpinAttempt
-	result := h.db.Where("user_id = ? AND attempt_date = ?", userID, today).First(&spinAttempt)
+	// The body is optional; without a campaign_id the spin draws from the base catalog
+	var req models.SpinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
 
-	if result.Error == nil {
-		// User has spun today - check if limit reached
-		if spinAttempt.AttemptsCount >= 3 {
-			utils.ErrorResponse(w, http.StatusTooManyRequests, "Daily spin limit reached")
+	var campaignID *uuid.UUID
+	if req.CampaignID != "" {
+		parsed, err := uuid.Parse(req.CampaignID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid campaign ID")
 			return
 		}
-		// Update existing record
-		spinAttempt.AttemptsCount++
-		spinAttempt.LastAttempt = now
-		h.db.Save(&spinAttempt)
-	} else if result.Error == gorm.ErrRecordNotFound {
-
-		spinAttempt = models.SpinAttempt{
-			ID:            uuid.New(),
-			UserID:        userID,
-			AttemptDate:   today,
-			AttemptsCount: 1,
-			LastAttempt:   now,
+		campaignID = &parsed
+	}
+
+	now := time.Now()
+
+	var drawn *models.Reward
+	var proof *models.SpinProof
+	var seed *models.SpinSeed
+	var userReward *models.UserReward
+	consolation := false
+	usedBonusSpin := false
+
+	// The limit check, attempt increment, draw and win are one transaction, so
+	// parallel spins can neither exceed the limit nor burn an attempt without a result
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		policy, err := resolveSpinPolicy(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		var rewards []models.Reward
+		if campaignID == nil {
+			// Check cooldown and weekly limits, then take one of today's spins,
+			// falling back to an earned bonus spin once the daily allowance is used up.
+			// Bonus spins do not lift the cooldown or the weekly limit.
+			if err := h.checkSpinAllowance(tx, userID, &policy, now); err != nil {
+				return err
+			}
+			err := h.consumeDailySpin(tx, userID, policy.SpinDay(now), now, policy.DailyLimit)
+			if errors.Is(err, errSpinLimitReached) {
+				err = h.consumeBonusSpin(tx, userID, policy.SpinDay(now), now)
+				usedBonusSpin = err == nil
+			}
+			if err != nil {
+				return err
+			}
+
+			// Get the base pool's rewards that still have stock
+			if err := tx.Where(models.RewardBasePoolCondition + " AND " + models.RewardInStockCondition).Order("id").Find(&rewards).Error; err != nil {
+				return err
+			}
+		} else {
+			// Campaign spins have their own limits and draw from the campaign's pool
+			var campaign models.Campaign
+			if err := tx.Where("id = ?", *campaignID).First(&campaign).Error; err != nil {
+				return err
+			}
+			if !campaign.IsRunning(now) {
+				return errCampaignNotRunning
+			}
+			if err := h.checkCampaignEligibility(tx, userID, &campaign, now); err != nil {
+				return err
+			}
+			if err := h.consumeCampaignSpin(tx, userID, &campaign, &policy, now); err != nil {
+				return err
+			}
+
+			rewards, err = h.loadCampaignRewards(tx, campaign.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(rewards) == 0 {
+			return errNoRewardsAvailable
+		}
+
+		// Draw from the committed seed so the result can be verified once the seed is revealed
+		seed, err = h.seeds.CurrentSeed(tx, now)
+		if err != nil {
+			return err
+		}
+		drawn, proof, err = h.selectRewardByProbability(tx, seed, userID, campaignID, rewards)
+		if err != nil {
+			return err
+		}
+
+		userReward, consolation, err = h.awardReward(tx, userID, drawn, campaignID, &policy, now)
+		if err != nil {
+			return err
+		}
+
+		return h.recordSpinEvent(tx, r, userID, campaignID, drawn, proof, userReward, consolation, now)
+	})
+
+	if err != nil {
+		var cooldown errSpinCooldown
+		var ineligible errCampaignIneligible
+		switch {
+		case errors.As(err, &cooldown):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(cooldown.nextSpinAt).Seconds()))))
+			utils.ErrorResponse(w, http.StatusTooManyRequests, "Please wait before spinning again")
+		case errors.As(err, &ineligible):
+			utils.ErrorResponse(w, http.StatusForbidden, ineligible.reason)
+		case errors.Is(err, errSpinLimitReached):
+			utils.ErrorResponse(w, http.StatusTooManyRequests, "Daily spin limit reached")
+		case errors.Is(err, errWeeklySpinLimitReached):
+			utils.ErrorResponse(w, http.StatusTooManyRequests, "Weekly spin limit reached")
+		case errors.Is(err, errCampaignLimitReached):
+			utils.ErrorResponse(w, http.StatusTooManyRequests, "Campaign spin limit reached")
+		case errors.Is(err, errNoRewardsAvailable):
+			utils.ErrorResponse(w, http.StatusInternalServerError, "No rewards available")
+		case errors.Is(err, gorm.ErrRecordNotFound) && campaignID != nil:
+			utils.ErrorResponse(w, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, errCampaignNotRunning):
+			utils.ErrorResponse(w, http.StatusBadRequest, "Campaign is not running")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to complete spin")
 		}
-		h.db.Create(&spinAttempt)
-	} else {
-		// Database error
-		utils.ErrorResponse(w, http.StatusInternalServerError, "Database error")
 		return
 	}
 
-	// Get all available rewards
-	var rewards []models.Reward
-	h.db.Where("is_active = true").Find(&rewards)
+	if userReward == nil {
+		// Either a "none" reward was drawn, or nothing with stock was left to award
+		var reward interface{}
+		if drawn.RewardType != nil && *drawn.RewardType == models.RewardTypeNone {
+			reward = drawn
+		}
 
-	if len(rewards) == 0 {
-		utils.ErrorResponse(w, http.StatusInternalServerError, "No rewards available")
+		utils.SuccessResponse(w, map[string]interface{}{
+			"success":    true,
+			"reward":     reward,
+			"message":    "Better luck next time!",
+			"bonus_spin": usedBonusSpin,
+			"fairness":   spinFairness(seed, proof),
+		})
 		return
 	}
 
-	// Select reward based on probability
-	selectedReward := h.selectRewardByProbability(rewards)
+	// Load reward details for response
+	h.db.Preload("Reward").First(userReward, userReward.ID)
+
+	message := "Congratulations! You won: " + userReward.Reward.Name
+	if consolation {
+		message = drawn.Name + " just ran out, so you won " + userReward.Reward.Name + " instead!"
+	}
 
-	if selectedReward.RewardType == nil || *selectedReward.RewardType != "none" {
-		expiryTime := now.AddDate(0, 0, 7) // 7 days from now
-		claimCode := h.generateClaimCode()
+	// Prepare response for REAL rewards
+	utils.SuccessResponse(w, map[string]interface{}{
+		"success":     true,
+		"reward":      userReward.Reward,
+		"message":     message,
+		"user_reward": userReward,
+		"claim_code":  userReward.ClaimCode,
+		"expires_at":  userReward.ExpiresAt,
+		"consolation": consolation,
+		"bonus_spin":  usedBonusSpin,
+		"fairness":    spinFairness(seed, proof),
+	})
+}
+
+// awardReward takes stock for the drawn reward and records the win, falling back
+// to the consolation reward if the drawn one sold out since it was loaded. It
+// returns a nil UserReward when a "none" reward was drawn or nothing could be awarded.
+func (h *LuckyDrawHandler) awardReward(tx *gorm.DB, userID uuid.UUID, drawn *models.Reward, campaignID *uuid.UUID, policy *models.SpinPolicy, now time.Time) (*models.UserReward, bool, error) {
+	if drawn.RewardType != nil && *drawn.RewardType == models.RewardTypeNone {
+		return nil, false, nil
+	}
+
+	// Take stock and record the win together so a reward is never handed out without stock
+	consolation := false
+	awarded, err := h.reserveReward(tx, drawn)
+	if err != nil {
+		return nil, false, err
+	}
 
-		userReward := models.UserReward{
-			ID:        uuid.New(),
-			UserID:    userID,
-			RewardID:  selectedReward.ID,
-			CreatedAt: now,
-			UpdatedAt: now,
-			Status:    "pending",
-			ExpiresAt: &expiryTime,
-			ClaimCode: &claimCode,
+	if awarded == nil {
+		awarded, err = h.reserveConsolationReward(tx, drawn.ID)
+		if err != nil || awarded == nil {
+			return nil, false, err
 		}
+		consolation = true
+	}
 
-		// Save to database
-		if err := h.db.Create(&userReward).Error; err != nil {
-			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to save reward")
-			return
+	expiryTime := now.AddDate(0, 0, policy.ClaimExpiryDays)
+	claimCode := h.generateClaimCode()
+	userReward := &models.UserReward{
+		ID:         uuid.New(),
+		UserID:     userID,
+		RewardID:   awarded.ID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Status:     "pending",
+		ExpiresAt:  &expiryTime,
+		ClaimCode:  &claimCode,
+		CampaignID: campaignID,
+	}
+
+	if err := tx.Create(userReward).Error; err != nil {
+		return nil, false, err
+	}
+	return userReward, consolation, nil
+}
+
+// recordSpinEvent appends the outcome of one spin to the spin_events audit log
+func (h *LuckyDrawHandler) recordSpinEvent(tx *gorm.DB, r *http.Request, userID uuid.UUID, campaignID *uuid.UUID, drawn *models.Reward, proof *models.SpinProof, userReward *models.UserReward, consolation bool, now time.Time) error {
+	ipAddress := clientIP(r, h.trustedProxies)
+	if len(ipAddress) > 45 {
+		ipAddress = ipAddress[:45]
+	}
+	event := models.SpinEvent{
+		ID:          uuid.New(),
+		UserID:      userID,
+		CampaignID:  campaignID,
+		SpinProofID: &proof.ID,
+		RandomValue: proof.RandomValue,
+		RewardID:    drawn.ID,
+		Outcome:     models.SpinOutcomeNoWin,
+		IPAddress:   &ipAddress,
+		CreatedAt:   now,
+	}
+	if deviceID, ok := r.Context().Value("device_id").(string); ok && deviceID != "" {
+		event.DeviceID = &deviceID
+	}
+	if userReward != nil {
+		event.UserRewardID = &userReward.ID
+		event.AwardedRewardID = &userReward.RewardID
+		event.Outcome = models.SpinOutcomeWon
+		if consolation {
+			event.Outcome = models.SpinOutcomeConsolation
 		}
+	}
+
+	return tx.Create(&event).Error
+}
+
+// checkSpinAllowance enforces the policy's cooldown and weekly limit. It locks
+// the user's row first so concurrent spins by the same user are checked one at a time.
+func (h *LuckyDrawHandler) checkSpinAllowance(tx *gorm.DB, userID uuid.UUID, policy *models.SpinPolicy, now time.Time) error {
+	if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", userID).Error; err != nil {
+		return err
+	}
+
+	usage, err := loadSpinUsage(tx, userID, policy, now)
+	if err != nil {
+		return err
+	}
 
-		// Load reward details for response
-		h.db.Preload("Reward").First(&userReward, userReward.ID)
-
-		// Prepare response for REAL rewards
-		response := map[string]interface{}{
-			"success":     true,
-			"reward":      selectedReward,
-			"message":     "Congratulations! You won: " + selectedReward.Name,
-			"user_reward": userReward,
-			"claim_code":  claimCode,
-			"expires_at":  &expiryTime,
+	if usage.lastSpin != nil && policy.CooldownSeconds > 0 {
+		if nextSpinAt := usage.lastSpin.Add(policy.Cooldown()); now.Before(nextSpinAt) {
+			return errSpinCooldown{nextSpinAt: nextSpinAt}
 		}
+	}
+
+	if policy.WeeklyLimit != nil && usage.usedThisWeek >= *policy.WeeklyLimit {
+		return errWeeklySpinLimitReached
+	}
 
-		utils.SuccessResponse(w, response)
-	} else {
-		response := map[string]interface{}{
-			"success": true,
-			"reward":  selectedReward,
-			"message": "Better luck next time!",
+	return nil
+}
+
+// spinUsage summarizes a user's spins within the current policy periods
+type spinUsage struct {
+	usedToday    int
+	usedThisWeek int
+	lastSpin     *time.Time
+}
+
+func loadSpinUsage(db *gorm.DB, userID uuid.UUID, policy *models.SpinPolicy, now time.Time) (spinUsage, error) {
+	var usage spinUsage
+	today := policy.SpinDay(now)
+
+	var attempts []models.SpinAttempt
+	if err := db.Where("user_id = ? AND attempt_date >= ?", userID, policy.SpinWeekStart(now)).Find(&attempts).Error; err != nil {
+		return usage, err
+	}
+
+	for i := range attempts {
+		attempt := &attempts[i]
+		usage.usedThisWeek += attempt.AttemptsCount
+		if attempt.AttemptDate.Equal(today) {
+			usage.usedToday = attempt.AttemptsCount
+		}
+		if usage.lastSpin == nil || attempt.LastAttempt.After(*usage.lastSpin) {
+			usage.lastSpin = &attempt.LastAttempt
 		}
+	}
 
-		utils.SuccessResponse(w, response)
+	return usage, nil
+}
+
+// consumeDailySpin records one spin for the user's day, failing with
+// errSpinLimitReached if the limit is already used up. The check and increment
+// are a single conditional upsert, so concurrent spins are serialized on the
+// spin_attempts row by Postgres rather than racing in Go.
+func (h *LuckyDrawHandler) consumeDailySpin(tx *gorm.DB, userID uuid.UUID, day, now time.Time, limit int) error {
+	var attemptsCount int
+	result := tx.Raw(`
+		INSERT INTO spin_attempts (id, user_id, attempt_date, attempts_count, last_attempt)
+		VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT (user_id, attempt_date) DO UPDATE
+		SET attempts_count = spin_attempts.attempts_count + 1,
+			last_attempt = EXCLUDED.last_attempt
+		WHERE spin_attempts.attempts_count < ?
+		RETURNING attempts_count`,
+		uuid.New(), userID, day, now, limit,
+	).Scan(&attemptsCount)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 || attemptsCount > limit {
+		return errSpinLimitReached
 	}
+	return nil
 }
 
-// selectRewardByProbability selects a reward based on probability weights
-func (h *LuckyDrawHandler) selectRewardByProbability(rewards []models.Reward) *models.Reward {
-	// Calculate total probability
-	var totalProbability float64
-	for _, reward := range rewards {
-		totalProbability += reward.Probability
+// consumeBonusSpin spends one earned bonus spin once the daily allowance is
+// used up, returning errSpinLimitReached if the user has none left. The spin is
+// stamped on the day's spin_attempts row without counting towards the daily or
+// weekly limit, so the cooldown still applies. The caller already holds the
+// user's row lock from checkSpinAllowance.
+func (h *LuckyDrawHandler) consumeBonusSpin(tx *gorm.DB, userID uuid.UUID, day, now time.Time) error {
+	consumed, err := h.bonus.Consume(tx, userID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return errSpinLimitReached
 	}
 
-	// Generate random number between 0 and totalProbability
-	randomBytes := make([]byte, 8)
-	rand.Read(randomBytes)
+	return tx.Exec(`
+		INSERT INTO spin_attempts (id, user_id, attempt_date, attempts_count, last_attempt)
+		VALUES (?, ?, ?, 0, ?)
+		ON CONFLICT (user_id, attempt_date) DO UPDATE
+		SET last_attempt = EXCLUDED.last_attempt`,
+		uuid.New(), userID, day, now,
+	).Error
+}
 
-	// Convert bytes to float64 between 0 and 1
-	randomFloat := float64(uint64(randomBytes[0])<<56|uint64(randomBytes[1])<<48|
-		uint64(randomBytes[2])<<40|uint64(randomBytes[3])<<32|
-		uint64(randomBytes[4])<<24|uint64(randomBytes[5])<<16|
-		uint64(randomBytes[6])<<8|uint64(randomBytes[7])) / math.MaxUint64
+// checkCampaignEligibility verifies the user meets the campaign's verification,
+// account age and tier rules
+func (h *LuckyDrawHandler) checkCampaignEligibility(tx *gorm.DB, userID uuid.UUID, campaign *models.Campaign, now time.Time) error {
+	var user models.User
+	if err := tx.Select("id", "is_verified", "tier", "created_at").Where("id = ?", userID).First(&user).Error; err != nil {
+		return err
+	}
 
-	randomValue := randomFloat * totalProbability
+	if campaign.RequiresVerification && !user.IsVerified {
+		return errCampaignIneligible{"This campaign is only open to verified users"}
+	}
 
-	// Select reward based on cumulative probability
-	var cumulative float64
-	for _, reward := range rewards {
-		cumulative += reward.Probability
-		if randomValue <= cumulative {
-			return &reward
+	if campaign.MinAccountAgeDays > 0 && now.Before(user.CreatedAt.AddDate(0, 0, campaign.MinAccountAgeDays)) {
+		return errCampaignIneligible{fmt.Sprintf("This campaign requires an account at least %d days old", campaign.MinAccountAgeDays)}
+	}
+
+	if tiers := campaign.TierList(); len(tiers) > 0 {
+		eligible := false
+		for _, tier := range tiers {
+			if user.Tier != nil && *user.Tier == tier {
+				eligible = true
+				break
+			}
+		}
+		if !eligible {
+			return errCampaignIneligible{"This campaign is not open to your tier"}
+		}
+	}
+
+	return nil
+}
+
+// consumeCampaignSpin records one campaign spin for the user's day. The daily
+// limit is the campaign's own, falling back to the spin policy's; the total
+// limit is checked under the user row lock so parallel spins cannot overshoot it.
+func (h *LuckyDrawHandler) consumeCampaignSpin(tx *gorm.DB, userID uuid.UUID, campaign *models.Campaign, policy *models.SpinPolicy, now time.Time) error {
+	if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", userID).Error; err != nil {
+		return err
+	}
+
+	if campaign.TotalSpinLimit != nil {
+		var used int64
+		if err := tx.Model(&models.CampaignSpinAttempt{}).
+			Select("COALESCE(SUM(attempts_count), 0)").
+			Where("user_id = ? AND campaign_id = ?", userID, campaign.ID).
+			Scan(&used).Error; err != nil {
+			return err
+		}
+		if used >= int64(*campaign.TotalSpinLimit) {
+			return errCampaignLimitReached
 		}
 	}
 
-	// Fallback to last reward (should not happen)
-	return &rewards[len(rewards)-1]
+	limit := policy.DailyLimit
+	if campaign.DailySpinLimit != nil {
+		limit = *campaign.DailySpinLimit
+	}
+
+	var attemptsCount int
+	result := tx.Raw(`
+		INSERT INTO campaign_spin_attempts (id, user_id, campaign_id, attempt_date, attempts_count, last_attempt)
+		VALUES (?, ?, ?, ?, 1, ?)
+		ON CONFLICT (user_id, campaign_id, attempt_date) DO UPDATE
+		SET attempts_count = campaign_spin_attempts.attempts_count + 1,
+			last_attempt = EXCLUDED.last_attempt
+		WHERE campaign_spin_attempts.attempts_count < ?
+		RETURNING attempts_count`,
+		uuid.New(), userID, campaign.ID, policy.SpinDay(now), now, limit,
+	).Scan(&attemptsCount)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 || attemptsCount > limit {
+		return errSpinLimitReached
+	}
+	return nil
+}
+
+// loadCampaignRewards returns the campaign's active, in-stock rewards with
+// their probabilities replaced by the campaign weights
+func (h *LuckyDrawHandler) loadCampaignRewards(tx *gorm.DB, campaignID uuid.UUID) ([]models.Reward, error) {
+	var entries []models.CampaignReward
+	if err := tx.Where("campaign_id = ?", campaignID).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	weights := make(map[uuid.UUID]float64, len(entries))
+	rewardIDs := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		weights[entry.RewardID] = entry.Probability
+		rewardIDs = append(rewardIDs, entry.RewardID)
+	}
+	if len(rewardIDs) == 0 {
+		return nil, nil
+	}
+
+	var rewards []models.Reward
+	if err := tx.Where("id IN ? AND is_active = true AND "+models.RewardInStockCondition, rewardIDs).
+		Order("id").
+		Find(&rewards).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range rewards {
+		rewards[i].Probability = weights[rewards[i].ID]
+	}
+	return rewards, nil
+}
+
+// reserveReward atomically takes one unit of stock from the reward, returning nil if none is left
+func (h *LuckyDrawHandler) reserveReward(tx *gorm.DB, reward *models.Reward) (*models.Reward, error) {
+	result := tx.Model(&models.Reward{}).
+		Where("id = ? AND is_active = ? AND "+models.RewardInStockCondition, reward.ID, true).
+		Update("total_claimed", gorm.Expr("total_claimed + 1"))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return reward, nil
+}
+
+// reserveConsolationReward takes stock from the configured consolation reward,
+// returning nil if there is none, it is the reward that just sold out, or it is out of stock too
+func (h *LuckyDrawHandler) reserveConsolationReward(tx *gorm.DB, soldOutID uuid.UUID) (*models.Reward, error) {
+	var consolation models.Reward
+	result := tx.Where("is_consolation = ? AND is_active = ? AND id <> ?", true, true, soldOutID).Limit(1).Find(&consolation)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+
+	if consolation.RewardType != nil && *consolation.RewardType == models.RewardTypeNone {
+		return nil, nil
+	}
+
+	return h.reserveReward(tx, &consolation)
+}
+
+// selectRewardByProbability draws from the rewards, which must be in a stable
+// order, using the value derived from the period seed and the user's next nonce.
+// The draw is recorded as a SpinProof so it can be reproduced after the reveal.
+// Callers must hold the user's row lock so nonces are handed out one at a time.
+func (h *LuckyDrawHandler) selectRewardByProbability(tx *gorm.DB, seed *models.SpinSeed, userID uuid.UUID, campaignID *uuid.UUID, rewards []models.Reward) (*models.Reward, *models.SpinProof, error) {
+	var lastNonce int64
+	if err := tx.Model(&models.SpinProof{}).
+		Select("COALESCE(MAX(nonce), 0)").
+		Where("seed_id = ? AND user_id = ?", seed.ID, userID).
+		Scan(&lastNonce).Error; err != nil {
+		return nil, nil, err
+	}
+
+	pool := make(models.SpinPool, len(rewards))
+	for i, reward := range rewards {
+		pool[i] = models.SpinPoolEntry{RewardID: reward.ID, Name: reward.Name, Probability: reward.Probability}
+	}
+
+	nonce := lastNonce + 1
+	value := services.DeriveSpinValue(seed.Seed, userID, nonce)
+	index, err := services.PickFromPool(pool, value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof := &models.SpinProof{
+		ID:          uuid.New(),
+		UserID:      userID,
+		SeedID:      seed.ID,
+		Nonce:       nonce,
+		RandomValue: value,
+		Pool:        pool,
+		RewardID:    rewards[index].ID,
+		CampaignID:  campaignID,
+	}
+	if err := tx.Create(proof).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &rewards[index], proof, nil
+}
+
+// spinFairness is the part of a spin response needed to verify it later
+func spinFairness(seed *models.SpinSeed, proof *models.SpinProof) map[string]interface{} {
+	return map[string]interface{}{
+		"spin_id":      proof.ID,
+		"seed_hash":    seed.SeedHash,
+		"period_start": seed.PeriodStart,
+		"period_end":   seed.PeriodEnd,
+		"nonce":        proof.Nonce,
+		"random_value": proof.RandomValue,
+	}
 }
 
 // generateClaimCode generates a random claim code
@@ -174,7 +623,7 @@ func (h *LuckyDrawHandler) generateClaimCode() string {
 // GetRewards - Get all available rewards
 func (h *LuckyDrawHandler) GetRewards(w http.ResponseWriter, r *http.Request) {
 	var rewards []models.Reward
-	result := h.db.Where("is_active = ?", true).Order("probability DESC").Find(&rewards)
+	result := h.db.Where(models.RewardBasePoolCondition).Order("probability DESC").Find(&rewards)
 
 	if result.Error != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch rewards")
@@ -271,6 +720,12 @@ func (h *LuckyDrawHandler) ClaimReward(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Physical rewards are handed over at a counter, where staff redeem the signed QR code
+	if userReward.Reward.RewardType != nil && *userReward.Reward.RewardType == models.RewardTypeProduct {
+		utils.ErrorResponse(w, http.StatusBadRequest, "This reward must be redeemed in person. Show its QR code at the counter")
+		return
+	}
+
 	location, _ := time.LoadLocation("Asia/Kolkata")
 	claimTime := time.Now().In(location)
 
@@ -281,8 +736,32 @@ func (h *LuckyDrawHandler) ClaimReward(w http.ResponseWriter, r *http.Request) {
 	log.Printf("DEBUG: Setting claimed_at to: %v (IST)", claimTime)
 	log.Printf("DEBUG: Date should show as: %s", claimTime.Format("02/01/2006"))
 
-	if h.db.Save(&userReward).Error != nil {
-		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to claim reward")
+	// Mark the reward claimed and credit any points together, guarding on the
+	// pending status so a double submit cannot claim or credit twice
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserReward{}).
+			Where("id = ? AND status = ?", userReward.ID, "pending").
+			Updates(map[string]interface{}{
+				"status":     userReward.Status,
+				"claimed_at": userReward.ClaimedAt,
+				"updated_at": userReward.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errRewardNotRedeemable
+		}
+
+		return creditPointsReward(tx, h.points, &userReward)
+	})
+
+	if err != nil {
+		if errors.Is(err, errRewardNotRedeemable) {
+			utils.ErrorResponse(w, http.StatusNotFound, "Invalid claim code or reward already claimed")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to claim reward")
+		}
 		return
 	}
 
@@ -305,19 +784,34 @@ func (h *LuckyDrawHandler) GetSpinHistory(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	var spinAttempts []models.SpinAttempt
-	result := h.db.
-		Where("user_id = ?", userIDStr).
-		Order("attempt_date DESC").
-		Limit(30). // Last 30 days
-		Find(&spinAttempts)
+	page, limit := parsePagination(r, 20)
+
+	query := h.db.Model(&models.SpinEvent{}).Where("user_id = ?", userIDStr)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin history")
+		return
+	}
+
+	var spins []models.SpinEvent
+	result := query.
+		Preload("Reward").
+		Preload("AwardedReward").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&spins)
 
 	if result.Error != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin history")
 		return
 	}
 
-	utils.SuccessResponse(w, spinAttempts)
+	utils.SuccessResponse(w, map[string]interface{}{
+		"spins":      spins,
+		"pagination": paginationResponse(page, limit, totalCount),
+	})
 }
 
 // GetUserStats - Get user's spin and reward statistics
@@ -335,12 +829,11 @@ func (h *LuckyDrawHandler) GetUserStats(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Calculate statistics
+	// Calculate statistics; every spin writes one spin event
 	var totalSpins int64
-	h.db.Model(&models.SpinAttempt{}).
-		Select("COALESCE(SUM(attempts_count), 0)").
+	h.db.Model(&models.SpinEvent{}).
 		Where("user_id = ?", userID).
-		Scan(&totalSpins)
+		Count(&totalSpins)
 
 	var totalWins int64
 	h.db.Model(&models.UserReward{}).
@@ -357,11 +850,17 @@ func (h *LuckyDrawHandler) GetUserStats(w http.ResponseWriter, r *http.Request)
 		Where("user_id = ? AND status = ?", userID, "claimed").
 		Count(&claimedRewards)
 
+	var expiredRewards int64
+	h.db.Model(&models.UserReward{}).
+		Where("user_id = ? AND status = ?", userID, "expired").
+		Count(&expiredRewards)
+
 	stats := models.UserRewardStats{
 		TotalSpins:     int(totalSpins),
 		TotalWins:      int(totalWins),
 		PendingRewards: int(pendingRewards),
 		ClaimedRewards: int(claimedRewards),
+		ExpiredRewards: int(expiredRewards),
 	}
 
 	utils.SuccessResponse(w, stats)
@@ -381,38 +880,63 @@ func (h *LuckyDrawHandler) GetRemainingSpins(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	location, _ := time.LoadLocation("Asia/Kolkata")
-	now := time.Now().In(location)
-
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-
-	var spinAttempt models.SpinAttempt
-	result := h.db.Where("user_id = ? AND attempt_date = ?", userID, today).First(&spinAttempt)
+	policy, err := resolveSpinPolicy(h.db, userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to load spin policy")
+		return
+	}
 
-	remainingSpins := 3
-	spinsUsed := 0
-	var lastSpin *time.Time
+	now := time.Now()
+	usage, err := loadSpinUsage(h.db, userID, &policy, now)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to check spin attempts")
+		return
+	}
 
-	if result.Error == nil {
-		spinsUsed = spinAttempt.AttemptsCount
-		remainingSpins = 3 - spinsUsed
-		if remainingSpins < 0 {
-			remainingSpins = 0
+	remainingSpins := policy.DailyLimit - usage.usedToday
+	var remainingThisWeek *int
+	if policy.WeeklyLimit != nil {
+		weekly := *policy.WeeklyLimit - usage.usedThisWeek
+		if weekly < 0 {
+			weekly = 0
 		}
-		if !spinAttempt.LastAttempt.IsZero() {
-			lastSpin = &spinAttempt.LastAttempt
+		remainingThisWeek = &weekly
+		if weekly < remainingSpins {
+			remainingSpins = weekly
 		}
-	} else if result.Error != gorm.ErrRecordNotFound {
-		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to check spin attempts")
+	}
+	if remainingSpins < 0 {
+		remainingSpins = 0
+	}
+
+	bonusSpins, err := h.bonus.Available(h.db, userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to check bonus spins")
 		return
 	}
+
+	// Bonus spins only stand in for the daily allowance, not the weekly limit
+	weeklyLimitReached := remainingThisWeek != nil && *remainingThisWeek == 0
+
+	var nextSpinAt *time.Time
+	if usage.lastSpin != nil && policy.CooldownSeconds > 0 {
+		if next := usage.lastSpin.Add(policy.Cooldown()); now.Before(next) {
+			nextSpinAt = &next
+		}
+	}
+
 	response := map[string]interface{}{
-		"remaining_spins":   remainingSpins,
-		"total_daily_spins": 3,
-		"spins_used_today":  spinsUsed,
-		"last_spin":         lastSpin,
-		"can_spin_today":    remainingSpins > 0,
-		"debug_today_date":  today.Format("2006-01-02"),
+		"remaining_spins":           remainingSpins,
+		"total_daily_spins":         policy.DailyLimit,
+		"spins_used_today":          usage.usedToday,
+		"weekly_spin_limit":         policy.WeeklyLimit,
+		"remaining_spins_this_week": remainingThisWeek,
+		"last_spin":                 usage.lastSpin,
+		"next_spin_at":              nextSpinAt,
+		"bonus_spins":               bonusSpins,
+		"can_spin_today":            remainingSpins > 0 || (bonusSpins > 0 && !weeklyLimitReached),
+		"reset_timezone":            policy.ResetTimezone,
+		"debug_today_date":          policy.SpinDay(now).Format("2006-01-02"),
 	}
 
 	utils.SuccessResponse(w, response)
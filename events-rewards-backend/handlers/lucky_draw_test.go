@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// setupSpinTest creates a user in a tier of its own, so the given policy
+// applies to nobody else, and a no-prize reward that lets every spin succeed
+// without touching stock
+func setupSpinTest(t *testing.T, db *gorm.DB, policy models.SpinPolicy) (models.User, *LuckyDrawHandler) {
+	t.Helper()
+
+	tier := "test-" + uuid.NewString()
+	policy.ID = uuid.New()
+	policy.Tier = &tier
+	policy.ResetTimezone = "UTC"
+	policy.ClaimExpiryDays = 7
+	policy.IsActive = true
+	if err := db.Create(&policy).Error; err != nil {
+		t.Fatalf("create spin policy: %v", err)
+	}
+
+	rewardType := models.RewardTypeNone
+	reward := models.Reward{
+		ID:          uuid.New(),
+		Name:        "Spin test prize",
+		RewardType:  &rewardType,
+		Probability: 1,
+		IsActive:    true,
+	}
+	if err := db.Create(&reward).Error; err != nil {
+		t.Fatalf("create reward: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Model(&models.Reward{}).Where("id = ?", reward.ID).Update("is_active", false)
+		db.Model(&models.SpinPolicy{}).Where("id = ?", policy.ID).Update("is_active", false)
+	})
+
+	user := createTestUser(t, db, &tier)
+	h := NewLuckyDrawHandler(db, services.NewSpinSeedService(db), services.NewPointsLedger(db), services.NewBonusSpinService(db), nil)
+	return user, h
+}
+
+// spin performs one spin as user and returns the response status
+func spin(h *LuckyDrawHandler, user models.User) int {
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/lucky-draw/spin", nil), user)
+	rec := httptest.NewRecorder()
+	h.Spin(rec, req)
+	return rec.Code
+}
+
+func TestSpinDailyLimitUnderConcurrency(t *testing.T) {
+	db := openTestDB(t)
+
+	const dailyLimit = 3
+	const attempts = 20
+
+	user, h := setupSpinTest(t, db, models.SpinPolicy{Name: "Concurrency test", DailyLimit: dailyLimit})
+
+	codes := make(chan int, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			codes <- spin(h, user)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(codes)
+
+	succeeded, limited := 0, 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			succeeded++
+		case http.StatusTooManyRequests:
+			limited++
+		default:
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+
+	if succeeded != dailyLimit {
+		t.Errorf("%d spins succeeded, want %d", succeeded, dailyLimit)
+	}
+	if limited != attempts-dailyLimit {
+		t.Errorf("%d spins were limited, want %d", limited, attempts-dailyLimit)
+	}
+
+	var recorded int64
+	if err := db.Model(&models.SpinAttempt{}).Select("COALESCE(SUM(attempts_count), 0)").Where("user_id = ?", user.ID).Scan(&recorded).Error; err != nil {
+		t.Fatalf("count spin attempts: %v", err)
+	}
+	if recorded != dailyLimit {
+		t.Errorf("%d spin attempts recorded, want %d", recorded, dailyLimit)
+	}
+}
+
+// grantBonusSpins gives the user spins to fall back on once their allowance is used up
+func grantBonusSpins(t *testing.T, db *gorm.DB, user models.User, spins int) models.BonusSpinGrant {
+	t.Helper()
+
+	grant := models.BonusSpinGrant{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Action:      models.BonusActionNewsRead,
+		ReferenceID: uuid.New(),
+		Spins:       spins,
+	}
+	if err := db.Create(&grant).Error; err != nil {
+		t.Fatalf("create bonus spin grant: %v", err)
+	}
+	return grant
+}
+
+// bonusSpinsUsed returns how many of the grant's spins have been spent
+func bonusSpinsUsed(t *testing.T, db *gorm.DB, grant models.BonusSpinGrant) int {
+	t.Helper()
+
+	var reloaded models.BonusSpinGrant
+	if err := db.First(&reloaded, "id = ?", grant.ID).Error; err != nil {
+		t.Fatalf("reload bonus spin grant: %v", err)
+	}
+	return reloaded.SpinsUsed
+}
+
+func TestSpinFallsBackToBonusSpinsAfterDailyLimit(t *testing.T) {
+	db := openTestDB(t)
+	user, h := setupSpinTest(t, db, models.SpinPolicy{Name: "Bonus fallback test", DailyLimit: 1})
+	grant := grantBonusSpins(t, db, user, 1)
+
+	if code := spin(h, user); code != http.StatusOK {
+		t.Fatalf("first spin returned %d, want 200", code)
+	}
+	if used := bonusSpinsUsed(t, db, grant); used != 0 {
+		t.Errorf("daily spin used %d bonus spins, want 0", used)
+	}
+
+	if code := spin(h, user); code != http.StatusOK {
+		t.Fatalf("spin past the daily limit returned %d, want 200 from a bonus spin", code)
+	}
+	if used := bonusSpinsUsed(t, db, grant); used != 1 {
+		t.Errorf("%d bonus spins used, want 1", used)
+	}
+
+	if code := spin(h, user); code != http.StatusTooManyRequests {
+		t.Errorf("spin with no bonus spins left returned %d, want 429", code)
+	}
+}
+
+func TestSpinBonusSpinsDoNotLiftWeeklyLimit(t *testing.T) {
+	db := openTestDB(t)
+	weeklyLimit := 1
+	user, h := setupSpinTest(t, db, models.SpinPolicy{Name: "Bonus weekly test", DailyLimit: 3, WeeklyLimit: &weeklyLimit})
+	grant := grantBonusSpins(t, db, user, 1)
+
+	if code := spin(h, user); code != http.StatusOK {
+		t.Fatalf("first spin returned %d, want 200", code)
+	}
+	if code := spin(h, user); code != http.StatusTooManyRequests {
+		t.Errorf("spin past the weekly limit returned %d, want 429", code)
+	}
+	if used := bonusSpinsUsed(t, db, grant); used != 0 {
+		t.Errorf("weekly limit spent %d bonus spins, want 0", used)
+	}
+}
+
+func TestSpinBonusSpinsRespectCooldown(t *testing.T) {
+	db := openTestDB(t)
+	policy := models.SpinPolicy{Name: "Bonus cooldown test", DailyLimit: 1, CooldownSeconds: 3600}
+	user, h := setupSpinTest(t, db, policy)
+	grant := grantBonusSpins(t, db, user, 2)
+
+	// Today's allowance was used up long enough ago for the cooldown to have passed
+	now := time.Now()
+	attempt := models.SpinAttempt{
+		ID:            uuid.New(),
+		UserID:        user.ID,
+		AttemptDate:   policy.SpinDay(now),
+		AttemptsCount: 1,
+		LastAttempt:   now.Add(-2 * time.Hour),
+	}
+	if err := db.Create(&attempt).Error; err != nil {
+		t.Fatalf("create spin attempt: %v", err)
+	}
+
+	if code := spin(h, user); code != http.StatusOK {
+		t.Fatalf("bonus spin returned %d, want 200", code)
+	}
+	if code := spin(h, user); code != http.StatusTooManyRequests {
+		t.Errorf("spin inside the cooldown returned %d, want 429", code)
+	}
+	if used := bonusSpinsUsed(t, db, grant); used != 1 {
+		t.Errorf("%d bonus spins used, want 1", used)
+	}
+}
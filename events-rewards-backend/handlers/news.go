@@ -6,19 +6,24 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Hritikpandey-ops/events-rewards-backend/middleware"
 	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
 	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type NewsHandler struct {
-	db *gorm.DB
+	db  *gorm.DB
+	bus *services.EventBus
 }
 
-func NewNewsHandler(db *gorm.DB) *NewsHandler {
-	return &NewsHandler{db: db}
+func NewNewsHandler(db *gorm.DB, bus *services.EventBus) *NewsHandler {
+	return &NewsHandler{db: db, bus: bus}
 }
 
 // GetNews - Get all published news with optional filtering
@@ -107,7 +112,7 @@ func (h *NewsHandler) GetNewsArticle(w http.ResponseWriter, r *http.Request) {
 	utils.SuccessResponse(w, news)
 }
 
-// CreateNews - Create a new news article (admin only - for now, any authenticated user)
+// CreateNews - Create a new news article (editors and admins)
 func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
 	userID, ok := r.Context().Value("user_id").(string)
@@ -176,7 +181,7 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var news models.News
-	result := h.db.Where("id = ? AND author_id = ?", newsID, userID).First(&news)
+	result := h.ownedNewsQuery(r, newsID, userID).First(&news)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(w, http.StatusNotFound, "News article not found or you don't have permission to edit it")
@@ -238,7 +243,7 @@ func (h *NewsHandler) DeleteNews(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var news models.News
-	result := h.db.Where("id = ? AND author_id = ?", newsID, userID).First(&news)
+	result := h.ownedNewsQuery(r, newsID, userID).First(&news)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(w, http.StatusNotFound, "News article not found or you don't have permission to delete it")
@@ -256,6 +261,15 @@ func (h *NewsHandler) DeleteNews(w http.ResponseWriter, r *http.Request) {
 	utils.MessageResponse(w, "News article deleted successfully")
 }
 
+// ownedNewsQuery scopes a news lookup to articles the user authored; admins may manage any article
+func (h *NewsHandler) ownedNewsQuery(r *http.Request, newsID, userID string) *gorm.DB {
+	query := h.db.Where("id = ?", newsID)
+	if !middleware.IsAdmin(r) {
+		query = query.Where("author_id = ?", userID)
+	}
+	return query
+}
+
 // GetMyNews - Get news articles created by the authenticated user
 func (h *NewsHandler) GetMyNews(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context
@@ -386,7 +400,7 @@ func (h *NewsHandler) TogglePublishStatus(w http.ResponseWriter, r *http.Request
 	}
 
 	var news models.News
-	result := h.db.Where("id = ? AND author_id = ?", newsID, userID).First(&news)
+	result := h.ownedNewsQuery(r, newsID, userID).First(&news)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(w, http.StatusNotFound, "News article not found or you don't have permission to edit it")
@@ -432,3 +446,49 @@ func (h *NewsHandler) BookmarkNews(w http.ResponseWriter, r *http.Request) {
 	// For now, just return success (implement actual bookmarking logic later)
 	utils.MessageResponse(w, "News article bookmarked successfully")
 }
+
+// MarkNewsRead - Record that the user has read a news article
+func (h *NewsHandler) MarkNewsRead(w http.ResponseWriter, r *http.Request) {
+	newsID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid news ID")
+		return
+	}
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var news models.News
+	if err := h.db.Select("id").Where("id = ? AND is_published = ?", newsID, true).First(&news).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "News article not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch news article")
+		}
+		return
+	}
+
+	read := models.NewsRead{
+		ID:     uuid.New(),
+		UserID: userID,
+		NewsID: newsID,
+		ReadAt: time.Now(),
+	}
+	result := h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&read)
+	if result.Error != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to record news read")
+		return
+	}
+
+	// Only the first read of an article counts towards bonus spins
+	if result.RowsAffected == 1 {
+		h.bus.Publish(services.EventNewsRead, map[string]interface{}{
+			"user_id":      userID,
+			"reference_id": newsID,
+		})
+	}
+
+	utils.MessageResponse(w, "News article marked as read")
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PointsHandler struct {
+	db     *gorm.DB
+	ledger *services.PointsLedger
+}
+
+func NewPointsHandler(db *gorm.DB, ledger *services.PointsLedger) *PointsHandler {
+	return &PointsHandler{db: db, ledger: ledger}
+}
+
+// GetBalance - Get the user's points balance
+func (h *PointsHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	balance, err := h.ledger.Balance(userID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch points balance")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"balance": balance,
+	})
+}
+
+// GetHistory - Get the user's points ledger entries, newest first
+func (h *PointsHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(r, 20)
+
+	query := h.db.Model(&models.PointsEntry{}).
+		Joins("JOIN points_accounts ON points_accounts.id = points_entries.account_id").
+		Where("points_accounts.kind = ? AND points_accounts.user_id = ?", models.PointsAccountUser, userID)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch points history")
+		return
+	}
+
+	var entries []models.PointsEntry
+	if err := query.
+		Preload("Transaction").
+		Order("points_entries.created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch points history")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"entries":    entries,
+		"pagination": paginationResponse(page, limit, totalCount),
+	})
+}
+
+// Reconcile - Check the points ledger balances against stored account balances (admin only)
+func (h *PointsHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	mismatches, err := h.ledger.Reconcile()
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to reconcile points ledger")
+		return
+	}
+
+	systemBalances := map[string]int64{}
+	for _, kind := range []string{models.PointsAccountRewardsPool, models.PointsAccountRedemptions} {
+		balance, err := h.ledger.SystemBalance(kind)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to reconcile points ledger")
+			return
+		}
+		systemBalances[kind] = balance
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"balanced":        len(mismatches) == 0,
+		"mismatches":      mismatches,
+		"system_balances": systemBalances,
+	})
+}
+
+// creditPointsReward credits the user for a claimed points reward. Other
+// reward types are left alone. It must run in the transaction that claims the reward.
+func creditPointsReward(tx *gorm.DB, ledger *services.PointsLedger, userReward *models.UserReward) error {
+	reward := userReward.Reward
+	if reward.ID == uuid.Nil {
+		if err := tx.Where("id = ?", userReward.RewardID).First(&reward).Error; err != nil {
+			return err
+		}
+	}
+
+	if reward.RewardType == nil || *reward.RewardType != models.RewardTypePoints || reward.Value == nil {
+		return nil
+	}
+
+	points := int64(math.Round(*reward.Value))
+	if points <= 0 {
+		return nil
+	}
+
+	_, err := ledger.Credit(tx, userReward.UserID, points, models.PointsTxnRewardCredit, "user_reward", userReward.ID, "Claimed "+reward.Name)
+	return err
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestPointsLedgerPost(t *testing.T) {
+	db := openTestDB(t)
+	ledger := services.NewPointsLedger(db)
+	user := createTestUser(t, db, nil)
+
+	credit := func(amount int64, reference uuid.UUID) (bool, error) {
+		var posted bool
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			posted, err = ledger.Credit(tx, user.ID, amount, models.PointsTxnRewardCredit, "user_reward", reference, "Test credit")
+			return err
+		})
+		return posted, err
+	}
+	debit := func(amount int64, reference uuid.UUID) (bool, error) {
+		var posted bool
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			posted, err = ledger.Debit(tx, user.ID, amount, models.PointsTxnRedemptionDebit, "order", reference, "Test debit")
+			return err
+		})
+		return posted, err
+	}
+
+	reference := uuid.New()
+	if posted, err := credit(100, reference); err != nil || !posted {
+		t.Fatalf("Credit = %v, %v, want true, nil", posted, err)
+	}
+
+	// Posting the same kind and reference again is a no-op
+	if posted, err := credit(100, reference); err != nil || posted {
+		t.Fatalf("repeated Credit = %v, %v, want false, nil", posted, err)
+	}
+
+	// A debit larger than the balance is rejected and rolls back entirely
+	overdraft := uuid.New()
+	if _, err := debit(150, overdraft); !errors.Is(err, services.ErrInsufficientPoints) {
+		t.Fatalf("overdraft Debit returned %v, want ErrInsufficientPoints", err)
+	}
+	var written int64
+	db.Model(&models.PointsTransaction{}).Where("reference_id = ?", overdraft).Count(&written)
+	if written != 0 {
+		t.Errorf("rejected debit left %d transactions behind", written)
+	}
+
+	if posted, err := debit(40, uuid.New()); err != nil || !posted {
+		t.Fatalf("Debit = %v, %v, want true, nil", posted, err)
+	}
+
+	balance, err := ledger.Balance(user.ID)
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	if balance != 60 {
+		t.Errorf("balance is %d, want 60", balance)
+	}
+
+	// System accounts are shared, exist once and take the other side of every posting
+	var pools []models.PointsAccount
+	db.Where("kind = ? AND user_id IS NULL", models.PointsAccountRewardsPool).Find(&pools)
+	if len(pools) != 1 {
+		t.Errorf("found %d rewards pool accounts, want 1", len(pools))
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		account, err := ledger.UserAccount(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		_, err = ledger.Post(tx, models.PointsTxnRewardCredit, "user_reward", uuid.New(), "Unbalanced", []services.PointsPosting{
+			{AccountID: account.ID, Amount: 10},
+		})
+		return err
+	})
+	if !errors.Is(err, services.ErrUnbalancedPosting) {
+		t.Errorf("unbalanced Post returned %v, want ErrUnbalancedPosting", err)
+	}
+}
+
+func TestPointsLedgerReconcile(t *testing.T) {
+	db := openTestDB(t)
+	ledger := services.NewPointsLedger(db)
+	user := createTestUser(t, db, nil)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := ledger.Credit(tx, user.ID, 25, models.PointsTxnRewardCredit, "user_reward", uuid.New(), "Test credit")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Credit: %v", err)
+	}
+
+	accountMismatches := func() []services.PointsMismatch {
+		t.Helper()
+		mismatches, err := ledger.Reconcile()
+		if err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		// Other tests share the database, so only look at this user's account
+		var own []services.PointsMismatch
+		for _, mismatch := range mismatches {
+			if mismatch.UserID != nil && *mismatch.UserID == user.ID {
+				own = append(own, mismatch)
+			}
+		}
+		return own
+	}
+
+	if mismatches := accountMismatches(); len(mismatches) != 0 {
+		t.Fatalf("Reconcile reported %+v for a consistent account", mismatches)
+	}
+
+	// Drift the stored balance away from the entries behind the ledger's back
+	db.Model(&models.PointsAccount{}).Where("kind = ? AND user_id = ?", models.PointsAccountUser, user.ID).Update("balance", 30)
+
+	mismatches := accountMismatches()
+	if len(mismatches) != 1 {
+		t.Fatalf("Reconcile reported %d mismatches, want 1", len(mismatches))
+	}
+	if mismatches[0].Expected != 25 || mismatches[0].Actual != 30 {
+		t.Errorf("mismatch expected %d, actual %d, want 25, 30", mismatches[0].Expected, mismatches[0].Actual)
+	}
+}
+
+func TestPointsBackfillClaimedRewards(t *testing.T) {
+	db := openTestDB(t)
+	ledger := services.NewPointsLedger(db)
+	user := createTestUser(t, db, nil)
+
+	rewardType := models.RewardTypePoints
+	value := 50.0
+	// Campaign-only keeps the reward out of the base spin pool other tests draw from
+	reward := models.Reward{
+		ID:           uuid.New(),
+		Name:         "Backfill test points",
+		RewardType:   &rewardType,
+		Value:        &value,
+		CampaignOnly: true,
+	}
+	if err := db.Create(&reward).Error; err != nil {
+		t.Fatalf("create reward: %v", err)
+	}
+	claimedAt := time.Now()
+	claim := models.UserReward{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		RewardID:  reward.ID,
+		Status:    "claimed",
+		ClaimedAt: &claimedAt,
+	}
+	if err := db.Create(&claim).Error; err != nil {
+		t.Fatalf("create user reward: %v", err)
+	}
+
+	// Running the backfill twice must not pay the claim twice
+	for i := 0; i < 2; i++ {
+		if _, err := ledger.BackfillClaimedRewards(); err != nil {
+			t.Fatalf("BackfillClaimedRewards: %v", err)
+		}
+	}
+
+	balance, err := ledger.Balance(user.ID)
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	if balance != 50 {
+		t.Errorf("balance after backfill is %d, want 50", balance)
+	}
+}
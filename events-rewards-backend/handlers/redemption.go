@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errRewardNotRedeemable = errors.New("reward is not pending")
+	errRewardExpired       = errors.New("reward has expired")
+)
+
+type RedemptionHandler struct {
+	db     *gorm.DB
+	tokens *services.RedemptionTokenService
+	points *services.PointsLedger
+}
+
+func NewRedemptionHandler(db *gorm.DB, tokens *services.RedemptionTokenService, points *services.PointsLedger) *RedemptionHandler {
+	return &RedemptionHandler{db: db, tokens: tokens, points: points}
+}
+
+// GetRewardQRCode - Get the signed QR code the winner shows at the counter, as a PNG
+func (h *RedemptionHandler) GetRewardQRCode(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var userReward models.UserReward
+	if err := h.db.Where("id = ? AND user_id = ?", mux.Vars(r)["id"], userID).First(&userReward).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Reward not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reward")
+		}
+		return
+	}
+
+	if userReward.Status != "pending" || userReward.ClaimCode == nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Reward has already been redeemed or is no longer available")
+		return
+	}
+	if userReward.ExpiresAt != nil && userReward.ExpiresAt.Before(time.Now()) {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Reward has expired")
+		return
+	}
+
+	png, err := h.tokens.QRCode(userReward.ID, *userReward.ClaimCode)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to generate QR code")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// RedeemReward - Redeem a scanned reward QR code at a counter (staff only)
+func (h *RedemptionHandler) RedeemReward(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RedeemRewardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	location := strings.TrimSpace(req.Location)
+	if location == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Location is required")
+		return
+	}
+
+	userRewardID, claimCode, err := h.tokens.Verify(req.Token)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid or tampered QR code")
+		return
+	}
+
+	var userReward models.UserReward
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the row so two counters scanning the same code cannot both redeem it
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND claim_code = ?", userRewardID, claimCode).
+			First(&userReward).Error; err != nil {
+			return err
+		}
+
+		if userReward.Status != "pending" {
+			return errRewardNotRedeemable
+		}
+
+		now := time.Now()
+		if userReward.ExpiresAt != nil && userReward.ExpiresAt.Before(now) {
+			return errRewardExpired
+		}
+
+		userReward.Status = "claimed"
+		userReward.ClaimedAt = &now
+		userReward.UpdatedAt = now
+		userReward.RedeemedBy = &staffID
+		userReward.RedemptionLocation = &location
+
+		if err := tx.Model(&userReward).Updates(map[string]interface{}{
+			"status":              userReward.Status,
+			"claimed_at":          userReward.ClaimedAt,
+			"updated_at":          userReward.UpdatedAt,
+			"redeemed_by":         userReward.RedeemedBy,
+			"redemption_location": userReward.RedemptionLocation,
+		}).Error; err != nil {
+			return err
+		}
+
+		return creditPointsReward(tx, h.points, &userReward)
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, "Reward not found")
+		case errors.Is(err, errRewardNotRedeemable):
+			utils.ErrorResponse(w, http.StatusConflict, "Reward has already been redeemed or is no longer available")
+		case errors.Is(err, errRewardExpired):
+			utils.ErrorResponse(w, http.StatusBadRequest, "Reward has expired")
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to redeem reward")
+		}
+		return
+	}
+
+	h.db.Preload("Reward").Preload("User").First(&userReward, userReward.ID)
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"message": "Reward redeemed successfully",
+		"reward":  userReward.Reward,
+		"winner": map[string]interface{}{
+			"id":         userReward.User.ID,
+			"first_name": userReward.User.FirstName,
+			"last_name":  userReward.User.LastName,
+		},
+		"user_reward_id":      userReward.ID,
+		"redeemed_at":         userReward.ClaimedAt,
+		"redemption_location": userReward.RedemptionLocation,
+	})
+}
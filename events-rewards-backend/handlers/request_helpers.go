@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+)
+
+// currentUserID reads the authenticated user's ID, writing an error response if it is missing
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userIDStr, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// parsePagination reads the page and limit query parameters, capping limit at 100
+func parsePagination(r *http.Request, defaultLimit int) (int, int) {
+	page := 1
+	limit := defaultLimit
+	if p := r.URL.Query().Get("page"); p != "" {
+		if parsedPage, err := strconv.Atoi(p); err == nil && parsedPage > 0 {
+			page = parsedPage
+		}
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+	return page, limit
+}
+
+// paginationResponse builds the pagination block used in list responses
+func paginationResponse(page, limit int, totalCount int64) map[string]interface{} {
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+	return map[string]interface{}{
+		"current_page": page,
+		"total_pages":  totalPages,
+		"total_count":  totalCount,
+		"has_next":     page < totalPages,
+		"has_prev":     page > 1,
+		"limit":        limit,
+	}
+}
+
+// clientIP returns the caller's address. Forwarding headers are only believed
+// when the request comes from one of the trusted proxies; X-Forwarded-For is
+// then read from the right, skipping trusted hops, so a client cannot choose
+// its address by sending the header itself.
+func clientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remote = host
+	}
+	if !isTrustedProxy(remote, trustedProxies) {
+		return remote
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop != "" && !isTrustedProxy(hop, trustedProxies) {
+				return hop
+			}
+		}
+		if first := strings.TrimSpace(hops[0]); first != "" {
+			return first
+		}
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return strings.TrimSpace(realIP)
+	}
+	return remote
+}
+
+// isTrustedProxy reports whether address falls inside one of the trusted proxy ranges
+func isTrustedProxy(address string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// probabilityTolerance absorbs floating point error when summing decimal(5,4) weights
+const probabilityTolerance = 1e-9
+
+// errRewardValidation wraps validation failures raised inside a transaction so
+// they can be reported as 400s rather than 500s
+type errRewardValidation struct {
+	message string
+}
+
+func (e errRewardValidation) Error() string {
+	return e.message
+}
+
+type RewardHandler struct {
+	db *gorm.DB
+}
+
+func NewRewardHandler(db *gorm.DB) *RewardHandler {
+	return &RewardHandler{db: db}
+}
+
+// ListRewards - Get the full reward catalog including inactive rewards (admin only)
+func (h *RewardHandler) ListRewards(w http.ResponseWriter, r *http.Request) {
+	query := h.db.Model(&models.Reward{})
+
+	if isActive := r.URL.Query().Get("is_active"); isActive != "" {
+		query = query.Where("is_active = ?", isActive == "true")
+	}
+
+	var rewards []models.Reward
+	if err := query.Order("is_active DESC, probability DESC").Find(&rewards).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch rewards")
+		return
+	}
+
+	var activeTotal float64
+	for _, reward := range rewards {
+		if reward.InBasePool() {
+			activeTotal += reward.Probability
+		}
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"rewards":                  rewards,
+		"active_probability_total": math.Round(activeTotal*10000) / 10000,
+	})
+}
+
+// GetReward - Get a single reward (admin only)
+func (h *RewardHandler) GetReward(w http.ResponseWriter, r *http.Request) {
+	var reward models.Reward
+	if err := h.db.Where("id = ?", mux.Vars(r)["id"]).First(&reward).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Reward not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reward")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, reward)
+}
+
+// CreateReward - Add a reward to the catalog (admin only)
+func (h *RewardHandler) CreateReward(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateRewardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	if err := validateRewardType(req.RewardType); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateProbability(req.Probability); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.TotalAvailable != nil && *req.TotalAvailable < 0 {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Total available cannot be negative")
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	reward := models.Reward{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		RewardType:     &req.RewardType,
+		Value:          req.Value,
+		Probability:    req.Probability,
+		TotalAvailable: req.TotalAvailable,
+		IsActive:       isActive,
+		IsConsolation:  req.IsConsolation,
+		CampaignOnly:   req.CampaignOnly,
+	}
+	if req.Description != "" {
+		reward.Description = &req.Description
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if reward.InBasePool() {
+			if err := checkActiveProbabilityTotal(tx, nil, reward.Probability); err != nil {
+				return err
+			}
+		}
+
+		if reward.IsConsolation {
+			if err := clearConsolationReward(tx, reward.ID); err != nil {
+				return err
+			}
+		}
+
+		// Create with an explicit column list so is_active=false isn't replaced by the column default
+		if err := tx.Select("*").Create(&reward).Error; err != nil {
+			return err
+		}
+
+		return recordWeightChange(tx, nil, &reward, adminID, "created", req.Reason)
+	})
+
+	if err != nil {
+		writeRewardError(w, err, "Failed to create reward")
+		return
+	}
+
+	utils.SuccessResponse(w, reward)
+}
+
+// UpdateReward - Edit or re-weight a reward (admin only)
+func (h *RewardHandler) UpdateReward(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	rewardID := mux.Vars(r)["id"]
+
+	var req models.UpdateRewardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.RewardType != nil {
+		if err := validateRewardType(*req.RewardType); err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if req.Probability != nil {
+		if err := validateProbability(*req.Probability); err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	var reward models.Reward
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", rewardID).First(&reward).Error; err != nil {
+			return err
+		}
+
+		before := reward
+
+		if req.Name != nil {
+			if strings.TrimSpace(*req.Name) == "" {
+				return errRewardValidation{"Name cannot be empty"}
+			}
+			reward.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Description != nil {
+			reward.Description = req.Description
+		}
+		if req.RewardType != nil {
+			reward.RewardType = req.RewardType
+		}
+		if req.Value != nil {
+			reward.Value = req.Value
+		}
+		if req.TotalAvailable != nil {
+			if *req.TotalAvailable < reward.TotalClaimed {
+				return errRewardValidation{fmt.Sprintf("Total available cannot be less than the %d already claimed", reward.TotalClaimed)}
+			}
+			reward.TotalAvailable = req.TotalAvailable
+		}
+		if req.Probability != nil {
+			reward.Probability = *req.Probability
+		}
+		if req.IsActive != nil {
+			reward.IsActive = *req.IsActive
+		}
+		if req.CampaignOnly != nil {
+			reward.CampaignOnly = *req.CampaignOnly
+		}
+		if req.IsConsolation != nil {
+			reward.IsConsolation = *req.IsConsolation
+			if reward.IsConsolation {
+				if err := clearConsolationReward(tx, reward.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		weightChanged := reward.Probability != before.Probability || reward.IsActive != before.IsActive
+		if weightChanged || reward.CampaignOnly != before.CampaignOnly {
+			if reward.InBasePool() {
+				if err := checkActiveProbabilityTotal(tx, &reward.ID, reward.Probability); err != nil {
+					return err
+				}
+			} else if before.InBasePool() {
+				if err := checkRemainingActiveRewards(tx, reward.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Save(&reward).Error; err != nil {
+			return err
+		}
+
+		if weightChanged {
+			return recordWeightChange(tx, &before, &reward, adminID, weightChangeType(&before, &reward), req.Reason)
+		}
+		return nil
+	})
+
+	if err != nil {
+		writeRewardError(w, err, "Failed to update reward")
+		return
+	}
+
+	utils.SuccessResponse(w, reward)
+}
+
+// DeleteReward - Deactivate a reward so it is no longer drawn (admin only).
+// Rewards are never removed because user_rewards reference them.
+func (h *RewardHandler) DeleteReward(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	rewardID := mux.Vars(r)["id"]
+	reason := r.URL.Query().Get("reason")
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var reward models.Reward
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", rewardID).First(&reward).Error; err != nil {
+			return err
+		}
+
+		if !reward.IsActive {
+			return nil
+		}
+
+		if reward.InBasePool() {
+			if err := checkRemainingActiveRewards(tx, reward.ID); err != nil {
+				return err
+			}
+		}
+
+		before := reward
+		reward.IsActive = false
+		if err := tx.Model(&reward).Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		return recordWeightChange(tx, &before, &reward, adminID, "deactivated", reason)
+	})
+
+	if err != nil {
+		writeRewardError(w, err, "Failed to deactivate reward")
+		return
+	}
+
+	utils.MessageResponse(w, "Reward deactivated successfully")
+}
+
+// GetRewardHistory - Get the weight change audit trail for a reward (admin only)
+func (h *RewardHandler) GetRewardHistory(w http.ResponseWriter, r *http.Request) {
+	rewardID := mux.Vars(r)["id"]
+
+	var count int64
+	if err := h.db.Model(&models.Reward{}).Where("id = ?", rewardID).Count(&count).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reward")
+		return
+	}
+	if count == 0 {
+		utils.ErrorResponse(w, http.StatusNotFound, "Reward not found")
+		return
+	}
+
+	var history []models.RewardWeightChange
+	if err := h.db.Where("reward_id = ?", rewardID).Order("created_at DESC").Find(&history).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch reward history")
+		return
+	}
+
+	utils.SuccessResponse(w, history)
+}
+
+// validateProbability enforces the same bounds and precision as the rewards table
+func validateProbability(probability float64) error {
+	if probability < models.MinRewardProbability || probability > models.MaxRewardProbability {
+		return fmt.Errorf("Probability must be between %.4f and %.4f", models.MinRewardProbability, models.MaxRewardProbability)
+	}
+	if math.Abs(math.Round(probability*10000)/10000-probability) > probabilityTolerance {
+		return errors.New("Probability can have at most 4 decimal places")
+	}
+	return nil
+}
+
+func validateRewardType(rewardType string) error {
+	switch rewardType {
+	case models.RewardTypeNone, models.RewardTypePoints, models.RewardTypeDiscount, models.RewardTypeProduct:
+		return nil
+	}
+	return errors.New("Reward type must be one of: none, points, discount, product")
+}
+
+// checkActiveProbabilityTotal ensures the base pool's probabilities, with
+// excludeID's replaced by probability, still sum to at most 1. Campaign-only
+// rewards are weighted per campaign and don't count. Pool rows are locked so
+// concurrent edits can't each pass the check and overshoot together.
+func checkActiveProbabilityTotal(tx *gorm.DB, excludeID *uuid.UUID, probability float64) error {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(models.RewardBasePoolCondition)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+
+	var others []models.Reward
+	if err := query.Find(&others).Error; err != nil {
+		return err
+	}
+
+	total := probability
+	for _, other := range others {
+		total += other.Probability
+	}
+
+	if total > 1+probabilityTolerance {
+		return errRewardValidation{fmt.Sprintf(
+			"Active reward probabilities would total %.4f; they must not exceed 1.0000 (%.4f available)",
+			total, math.Max(0, 1-(total-probability)))}
+	}
+	return nil
+}
+
+// checkRemainingActiveRewards prevents removing the last reward from the base pool, which would break the draw
+func checkRemainingActiveRewards(tx *gorm.DB, rewardID uuid.UUID) error {
+	var remaining int64
+	if err := tx.Model(&models.Reward{}).Where(models.RewardBasePoolCondition+" AND id <> ?", rewardID).Count(&remaining).Error; err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return errRewardValidation{"At least one reward must remain active"}
+	}
+	return nil
+}
+
+// clearConsolationReward unflags any other consolation reward so only one is configured at a time
+func clearConsolationReward(tx *gorm.DB, keepID uuid.UUID) error {
+	return tx.Model(&models.Reward{}).
+		Where("is_consolation = ? AND id <> ?", true, keepID).
+		Update("is_consolation", false).Error
+}
+
+func weightChangeType(before, after *models.Reward) string {
+	switch {
+	case before.IsActive && !after.IsActive:
+		return "deactivated"
+	case !before.IsActive && after.IsActive:
+		return "reactivated"
+	default:
+		return "reweighted"
+	}
+}
+
+// recordWeightChange appends an entry to reward_weight_history
+func recordWeightChange(tx *gorm.DB, before, after *models.Reward, changedBy uuid.UUID, changeType, reason string) error {
+	change := models.RewardWeightChange{
+		RewardID:       after.ID,
+		ChangedBy:      changedBy,
+		ChangeType:     changeType,
+		NewProbability: after.Probability,
+		NewIsActive:    after.IsActive,
+		CreatedAt:      time.Now(),
+	}
+	if before != nil {
+		change.OldProbability = &before.Probability
+		change.OldIsActive = &before.IsActive
+	}
+	if reason != "" {
+		change.Reason = &reason
+	}
+
+	return tx.Create(&change).Error
+}
+
+func writeRewardError(w http.ResponseWriter, err error, fallback string) {
+	var validationErr errRewardValidation
+	switch {
+	case errors.As(err, &validationErr):
+		utils.ErrorResponse(w, http.StatusBadRequest, validationErr.message)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, "Reward not found")
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, fallback)
+	}
+}
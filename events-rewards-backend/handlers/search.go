@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// searchHeadlineOptions configures ts_headline snippets: matched terms are
+// wrapped in <mark> tags and up to two fragments are joined with an ellipsis
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+
+const maxSearchQueryLength = 200
+
+// htmlEscapedSQL wraps a SQL text expression so it is HTML-escaped before
+// ts_headline adds its <mark> tags. Clients render headlines as HTML, and
+// without this any markup in an event or article would be rendered with them.
+func htmlEscapedSQL(expr string) string {
+	// & goes first so the entities added for the other characters are not escaped again
+	for _, pair := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"'", "&#39;"}} {
+		expr = "replace(" + expr + ", " + sqlStringLiteral(pair[0]) + ", " + sqlStringLiteral(pair[1]) + ")"
+	}
+	return expr
+}
+
+// sqlStringLiteral quotes a constant for use as a SQL string literal
+func sqlStringLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+type SearchHandler struct {
+	db *gorm.DB
+}
+
+func NewSearchHandler(db *gorm.DB) *SearchHandler {
+	return &SearchHandler{db: db}
+}
+
+// parseSearchTypes reads the comma-separated type filter, defaulting to every type
+func parseSearchTypes(raw string) (map[string]bool, bool) {
+	types := map[string]bool{}
+	if raw == "" || raw == "all" {
+		types[models.SearchTypeEvent] = true
+		types[models.SearchTypeNews] = true
+		return types, true
+	}
+	for _, value := range strings.Split(raw, ",") {
+		switch value = strings.TrimSpace(strings.ToLower(value)); value {
+		case models.SearchTypeEvent, "events":
+			types[models.SearchTypeEvent] = true
+		case models.SearchTypeNews:
+			types[models.SearchTypeNews] = true
+		default:
+			return nil, false
+		}
+	}
+	return types, true
+}
+
+// Search - Full-text search across events and published news, best matches first
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Search query is required")
+		return
+	}
+	if len(q) > maxSearchQueryLength {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Search query is too long")
+		return
+	}
+
+	types, ok := parseSearchTypes(r.URL.Query().Get("type"))
+	if !ok {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Type must be event, news or all")
+		return
+	}
+
+	category := r.URL.Query().Get("category")
+	includePast := r.URL.Query().Get("include_past") == "true"
+	page, limit := parsePagination(r, 10)
+	offset := (page - 1) * limit
+
+	// websearch_to_tsquery accepts what users type into a search box, including
+	// quoted phrases, "or" and -exclusions, and never fails on bad syntax
+	args := []interface{}{q}
+	var hits []string
+
+	if types[models.SearchTypeEvent] {
+		hit := `SELECT 'event' AS type, e.id, ts_rank_cd(e.search_vector, search.query) AS rank, e.event_date AS date
+			FROM events e, search
+			WHERE e.search_vector @@ search.query AND e.is_active AND e.deleted_at IS NULL`
+		if !includePast {
+			hit += " AND e.event_date >= ?"
+			args = append(args, time.Now())
+		}
+		if category != "" {
+			hit += " AND e.category = ?"
+			args = append(args, category)
+		}
+		hits = append(hits, hit)
+	}
+
+	if types[models.SearchTypeNews] {
+		hit := `SELECT 'news' AS type, n.id, ts_rank_cd(n.search_vector, search.query) AS rank, n.publish_date AS date
+			FROM news n, search
+			WHERE n.search_vector @@ search.query AND n.is_published AND n.deleted_at IS NULL`
+		if category != "" {
+			hit += " AND n.category = ?"
+			args = append(args, category)
+		}
+		hits = append(hits, hit)
+	}
+
+	with := `WITH search AS (SELECT websearch_to_tsquery('english', ?) AS query),
+		hits AS (` + strings.Join(hits, " UNION ALL ") + `)`
+
+	var totalCount int64
+	if err := h.db.Raw(with+" SELECT count(*) FROM hits", args...).Scan(&totalCount).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to search")
+		return
+	}
+
+	// Headlines are costly, so they are only built for the rows on this page
+	results := []models.SearchResult{}
+	pageArgs := append(append([]interface{}{}, args...), limit, offset, searchHeadlineOptions)
+	titleText := htmlEscapedSQL("coalesce(e.title, n.title)")
+	snippetText := htmlEscapedSQL("coalesce(e.description, n.summary || ' ' || n.content, n.content, '')")
+	err := h.db.Raw(with+`,
+		page AS (SELECT * FROM hits ORDER BY rank DESC, date DESC NULLS LAST, id LIMIT ? OFFSET ?)
+		SELECT page.type, page.id, page.rank, page.date,
+			ts_headline('english', `+titleText+`, search.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title,
+			ts_headline('english', `+snippetText+`, search.query, ?) AS snippet,
+			coalesce(e.category, n.category) AS category
+		FROM page
+		CROSS JOIN search
+		LEFT JOIN events e ON page.type = 'event' AND e.id = page.id
+		LEFT JOIN news n ON page.type = 'news' AND n.id = page.id
+		ORDER BY page.rank DESC, page.date DESC NULLS LAST, page.id`, pageArgs...).
+		Scan(&results).Error
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to search")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"query":      q,
+		"results":    results,
+		"pagination": paginationResponse(page, limit, totalCount),
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+
+	"github.com/google/uuid"
+)
+
+func TestHTMLEscapedSQL(t *testing.T) {
+	want := `replace(replace(replace(replace(replace(e.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+	if got := htmlEscapedSQL("e.title"); got != want {
+		t.Errorf("htmlEscapedSQL = %s, want %s", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db := openTestDB(t)
+	h := NewSearchHandler(db)
+
+	// A made-up word keeps rows left behind by other runs out of the results.
+	// Digits are mapped to letters so the parser reads it as a single word.
+	term := "zq" + strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return 'g' + (r - '0')
+		}
+		return r
+	}, strings.ReplaceAll(uuid.New().String(), "-", "")[:10])
+
+	upcoming := createTestEvent(t, db, 10)
+	db.Model(&upcoming).Update("title", "<script>alert(1)</script> "+term+" meetup")
+	described := createTestEvent(t, db, 10)
+	db.Model(&described).Update("description", "An evening about "+term)
+	past := createTestEvent(t, db, 10)
+	db.Model(&past).Updates(map[string]interface{}{"title": term + " retrospective", "event_date": time.Now().Add(-24 * time.Hour)})
+
+	now := time.Now()
+	for i, published := range []bool{true, true, false} {
+		news := models.News{
+			ID:          uuid.New(),
+			Title:       "News " + term,
+			Content:     "Story number " + strconv.Itoa(i+1),
+			IsPublished: published,
+			AuthorID:    uuid.New().String(),
+			PublishDate: &now,
+		}
+		if err := db.Create(&news).Error; err != nil {
+			t.Fatalf("create news: %v", err)
+		}
+	}
+
+	type searchResponse struct {
+		Data struct {
+			Results    []models.SearchResult `json:"results"`
+			Pagination struct {
+				TotalCount int64 `json:"total_count"`
+				TotalPages int   `json:"total_pages"`
+			} `json:"pagination"`
+		} `json:"data"`
+	}
+	search := func(params url.Values) (int, searchResponse) {
+		t.Helper()
+		params.Set("q", term)
+		rec := httptest.NewRecorder()
+		h.Search(rec, httptest.NewRequest(http.MethodGet, "/api/v1/search?"+params.Encode(), nil))
+
+		var resp searchResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode search response: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   int64
+	}{
+		{"all types", url.Values{}, 4},
+		{"events only", url.Values{"type": {"event"}}, 2},
+		{"news only", url.Values{"type": {"news"}}, 2},
+		{"past events included", url.Values{"type": {"event"}, "include_past": {"true"}}, 3},
+	}
+	for _, tt := range tests {
+		code, resp := search(tt.params)
+		if code != http.StatusOK {
+			t.Errorf("%s: search returned %d, want 200", tt.name, code)
+			continue
+		}
+		if resp.Data.Pagination.TotalCount != tt.want || int64(len(resp.Data.Results)) != tt.want {
+			t.Errorf("%s: %d results of %d total, want %d", tt.name, len(resp.Data.Results), resp.Data.Pagination.TotalCount, tt.want)
+		}
+		for _, result := range resp.Data.Results {
+			if result.ID == past.ID && tt.params.Get("include_past") != "true" {
+				t.Errorf("%s: past event was returned", tt.name)
+			}
+		}
+	}
+
+	// The second page holds whatever the first page could not
+	_, resp := search(url.Values{"limit": {"3"}, "page": {"2"}})
+	if len(resp.Data.Results) != 1 || resp.Data.Pagination.TotalCount != 4 || resp.Data.Pagination.TotalPages != 2 {
+		t.Errorf("page 2 has %d results of %d total over %d pages, want 1 of 4 over 2",
+			len(resp.Data.Results), resp.Data.Pagination.TotalCount, resp.Data.Pagination.TotalPages)
+	}
+
+	if code, _ := search(url.Values{"type": {"people"}}); code != http.StatusBadRequest {
+		t.Errorf("unknown type returned %d, want 400", code)
+	}
+
+	// Markup in the title is escaped; the only tags left are the highlights
+	_, resp = search(url.Values{"type": {"event"}})
+	var title string
+	for _, result := range resp.Data.Results {
+		if result.ID == upcoming.ID {
+			title = result.Title
+		}
+	}
+	if strings.Contains(title, "<script>") || !strings.Contains(title, "&lt;script&gt;") {
+		t.Errorf("title %q was not HTML-escaped", title)
+	}
+	if !strings.Contains(title, "<mark>"+term+"</mark>") {
+		t.Errorf("title %q does not highlight the search term", title)
+	}
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type SpinPolicyHandler struct {
+	db *gorm.DB
+}
+
+func NewSpinPolicyHandler(db *gorm.DB) *SpinPolicyHandler {
+	return &SpinPolicyHandler{db: db}
+}
+
+// ListPolicies - Get all spin policies (admin only)
+func (h *SpinPolicyHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	var policies []models.SpinPolicy
+	if err := h.db.Order("tier NULLS FIRST, created_at").Find(&policies).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin policies")
+		return
+	}
+
+	utils.SuccessResponse(w, map[string]interface{}{
+		"policies":         policies,
+		"built_in_default": models.DefaultSpinPolicy(),
+	})
+}
+
+// CreatePolicy - Create the default spin policy or a per-tier override (admin only)
+func (h *SpinPolicyHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSpinPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	policy := models.DefaultSpinPolicy()
+	policy.ID = uuid.New()
+	policy.Name = strings.TrimSpace(req.Name)
+	policy.WeeklyLimit = req.WeeklyLimit
+	policy.CooldownSeconds = req.CooldownSeconds
+	policy.UpdatedBy = &adminID
+	if req.DailyLimit != 0 {
+		policy.DailyLimit = req.DailyLimit
+	}
+	if req.ResetTimezone != "" {
+		policy.ResetTimezone = req.ResetTimezone
+	}
+	if req.ClaimExpiryDays != 0 {
+		policy.ClaimExpiryDays = req.ClaimExpiryDays
+	}
+	if req.Tier != nil && strings.TrimSpace(*req.Tier) != "" {
+		tier := strings.TrimSpace(*req.Tier)
+		policy.Tier = &tier
+	}
+
+	if policy.Name == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	if err := validateSpinPolicy(&policy); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The tier column is unique, but NULLs never collide, so check for a second default by hand
+	existing := h.db.Model(&models.SpinPolicy{})
+	if policy.Tier == nil {
+		existing = existing.Where("tier IS NULL")
+	} else {
+		existing = existing.Where("tier = ?", *policy.Tier)
+	}
+	var count int64
+	if err := existing.Count(&count).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create spin policy")
+		return
+	}
+	if count > 0 {
+		utils.ErrorResponse(w, http.StatusConflict, "A spin policy already exists for this tier; update it instead")
+		return
+	}
+
+	if err := h.db.Create(&policy).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create spin policy")
+		return
+	}
+
+	utils.SuccessResponse(w, policy)
+}
+
+// UpdatePolicy - Edit a spin policy (admin only)
+func (h *SpinPolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSpinPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var policy models.SpinPolicy
+	if err := h.db.Where("id = ?", mux.Vars(r)["id"]).First(&policy).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Spin policy not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch spin policy")
+		}
+		return
+	}
+
+	if req.Name != nil {
+		policy.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.DailyLimit != nil {
+		policy.DailyLimit = *req.DailyLimit
+	}
+	if req.WeeklyLimit != nil {
+		policy.WeeklyLimit = req.WeeklyLimit
+	}
+	if req.ClearWeeklyLimit {
+		policy.WeeklyLimit = nil
+	}
+	if req.ResetTimezone != nil {
+		policy.ResetTimezone = *req.ResetTimezone
+	}
+	if req.CooldownSeconds != nil {
+		policy.CooldownSeconds = *req.CooldownSeconds
+	}
+	if req.ClaimExpiryDays != nil {
+		policy.ClaimExpiryDays = *req.ClaimExpiryDays
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	policy.UpdatedBy = &adminID
+
+	if policy.Name == "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Name cannot be empty")
+		return
+	}
+	if err := validateSpinPolicy(&policy); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.db.Save(&policy).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update spin policy")
+		return
+	}
+
+	utils.SuccessResponse(w, policy)
+}
+
+func validateSpinPolicy(policy *models.SpinPolicy) error {
+	if policy.DailyLimit < 0 {
+		return errors.New("Daily limit cannot be negative")
+	}
+	if policy.WeeklyLimit != nil && *policy.WeeklyLimit < 0 {
+		return errors.New("Weekly limit cannot be negative")
+	}
+	if policy.CooldownSeconds < 0 {
+		return errors.New("Cooldown cannot be negative")
+	}
+	if policy.ClaimExpiryDays < 1 {
+		return errors.New("Claim expiry must be at least 1 day")
+	}
+	if _, err := time.LoadLocation(policy.ResetTimezone); err != nil {
+		return errors.New("Reset timezone must be a valid IANA timezone such as Asia/Kolkata")
+	}
+	return nil
+}
+
+// resolveSpinPolicy returns the active policy for the user's tier, falling back
+// to the active default policy and then to the built-in default
+func resolveSpinPolicy(db *gorm.DB, userID uuid.UUID) (models.SpinPolicy, error) {
+	var user models.User
+	if err := db.Select("id", "tier").Where("id = ?", userID).First(&user).Error; err != nil {
+		return models.SpinPolicy{}, err
+	}
+
+	var policies []models.SpinPolicy
+	query := db.Where("is_active = ?", true)
+	if user.Tier != nil {
+		query = query.Where("tier = ? OR tier IS NULL", *user.Tier)
+	} else {
+		query = query.Where("tier IS NULL")
+	}
+	if err := query.Order("tier NULLS LAST").Limit(1).Find(&policies).Error; err != nil {
+		return models.SpinPolicy{}, err
+	}
+
+	if len(policies) == 0 {
+		return models.DefaultSpinPolicy(), nil
+	}
+	return policies[0], nil
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDatabaseURLEnv names the Postgres DSN used by tests that need a real
+// database. Those tests are skipped when it is not set. Point it at a
+// disposable database: the tests migrate the schema and leave rows behind.
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+// openTestDB connects to the test database and migrates the schema, skipping the test if none is configured
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error; err != nil {
+		t.Fatalf("create uuid-ossp extension: %v", err)
+	}
+	err = db.AutoMigrate(
+		&models.User{},
+		&models.Event{},
+		&models.EventRegistration{},
+		&models.Reward{},
+		&models.UserReward{},
+		&models.SpinAttempt{},
+		&models.SpinPolicy{},
+		&models.SpinSeed{},
+		&models.SpinProof{},
+		&models.SpinEvent{},
+		&models.PointsAccount{},
+		&models.PointsTransaction{},
+		&models.PointsEntry{},
+		&models.CatalogItem{},
+		&models.CatalogOrder{},
+		&models.BonusSpinRule{},
+		&models.BonusSpinGrant{},
+		&models.TicketTier{},
+		&models.News{},
+	)
+	if err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	// main.go creates this index outside AutoMigrate; registration relies on it to reject duplicates
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_event_registrations_user_event
+		ON event_registrations (user_id, event_id)`).Error; err != nil {
+		t.Fatalf("create registration index: %v", err)
+	}
+
+	// The full-text search vectors are generated columns, also added by main.go
+	for _, statement := range []string{
+		`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(category, '') || ' ' || coalesce(venue, '') || ' ' || coalesce(location, '')), 'C')
+		) STORED`,
+		`ALTER TABLE news ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(summary, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(content, '')), 'C') ||
+			setweight(to_tsvector('english', coalesce(category, '')), 'D')
+		) STORED`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("add search vector: %v", err)
+		}
+	}
+
+	return db
+}
+
+// createTestUser inserts a user with a unique email
+func createTestUser(t *testing.T, db *gorm.DB, tier *string) models.User {
+	t.Helper()
+
+	id := uuid.New()
+	user := models.User{
+		ID:           id,
+		Email:        id.String() + "@test.local",
+		PasswordHash: "not-a-real-hash",
+		FirstName:    "Test",
+		LastName:     "User",
+		IsActive:     true,
+		Role:         models.RoleUser,
+		Tier:         tier,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create test user: %v", err)
+	}
+	return user
+}
+
+// createTestEvent inserts an active event a week from now, limited to capacity seats
+func createTestEvent(t *testing.T, db *gorm.DB, capacity int) models.Event {
+	t.Helper()
+
+	event := models.Event{
+		ID:              uuid.New(),
+		Title:           "Test event",
+		EventDate:       time.Now().Add(7 * 24 * time.Hour),
+		MaxParticipants: &capacity,
+		IsActive:        true,
+	}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatalf("create test event: %v", err)
+	}
+	return event
+}
+
+// withUser returns r carrying the authenticated user the auth middleware would set
+func withUser(r *http.Request, user models.User) *http.Request {
+	ctx := context.WithValue(r.Context(), "user_id", user.ID.String())
+	ctx = context.WithValue(ctx, "email", user.Email)
+	ctx = context.WithValue(ctx, "role", user.Role)
+	return r.WithContext(ctx)
+}
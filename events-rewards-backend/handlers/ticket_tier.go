@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errInvalidTicketTier is returned when an updated tier fails validation
+type errInvalidTicketTier struct {
+	reason string
+}
+
+func (e errInvalidTicketTier) Error() string {
+	return e.reason
+}
+
+type TicketTierHandler struct {
+	db *gorm.DB
+}
+
+func NewTicketTierHandler(db *gorm.DB) *TicketTierHandler {
+	return &TicketTierHandler{db: db}
+}
+
+// ticketTierResponse adds the computed availability fields to a tier
+func ticketTierResponse(tier *models.TicketTier, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"tier":      tier,
+		"remaining": tier.Remaining(),
+		"on_sale":   tier.OnSale(now),
+	}
+}
+
+// ListTiers - Get the active ticket tiers for an event
+func (h *TicketTierHandler) ListTiers(w http.ResponseWriter, r *http.Request) {
+	var tiers []models.TicketTier
+	if err := h.db.Where("event_id = ? AND is_active = ?", mux.Vars(r)["id"], true).
+		Order("sort_order, price").
+		Find(&tiers).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch ticket tiers")
+		return
+	}
+
+	now := time.Now()
+	response := make([]map[string]interface{}, 0, len(tiers))
+	for i := range tiers {
+		response = append(response, ticketTierResponse(&tiers[i], now))
+	}
+
+	utils.SuccessResponse(w, response)
+}
+
+// managedEvent loads the event in the request path, writing an error response
+// unless the caller created it or is an admin
+func (h *TicketTierHandler) managedEvent(w http.ResponseWriter, r *http.Request) (*models.Event, bool) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	var event models.Event
+	if err := h.db.Where("id = ?", mux.Vars(r)["id"]).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(w, http.StatusNotFound, "Event not found")
+		} else {
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch event")
+		}
+		return nil, false
+	}
+
+	if !canManageEvent(r, &event, userID.String()) {
+		utils.ErrorResponse(w, http.StatusForbidden, "You can only manage ticket tiers for your own events")
+		return nil, false
+	}
+
+	return &event, true
+}
+
+// validateTicketTier checks a tier's fields, returning a message describing the first problem
+func validateTicketTier(tier *models.TicketTier) string {
+	switch {
+	case strings.TrimSpace(tier.Name) == "":
+		return "Name is required"
+	case tier.Price < 0:
+		return "Price cannot be negative"
+	case tier.Capacity != nil && *tier.Capacity < 0:
+		return "Capacity cannot be negative"
+	case tier.Capacity != nil && *tier.Capacity < tier.Sold:
+		return "Capacity cannot be less than the number of tickets already sold"
+	case tier.PerUserLimit < 1:
+		return "Per-user limit must be at least 1"
+	case tier.SalesStartAt != nil && tier.SalesEndAt != nil && !tier.SalesEndAt.After(*tier.SalesStartAt):
+		return "Sales end must be after sales start"
+	}
+	return ""
+}
+
+// CreateTier - Add a ticket tier to an event (event organizer or admin)
+func (h *TicketTierHandler) CreateTier(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.managedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTicketTierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tier := models.TicketTier{
+		ID:           uuid.New(),
+		EventID:      event.ID,
+		Name:         strings.TrimSpace(req.Name),
+		Description:  req.Description,
+		Price:        req.Price,
+		Capacity:     req.Capacity,
+		PerUserLimit: 1,
+		SalesStartAt: req.SalesStartAt,
+		SalesEndAt:   req.SalesEndAt,
+		SortOrder:    req.SortOrder,
+		IsActive:     true,
+	}
+	if req.PerUserLimit != nil {
+		tier.PerUserLimit = *req.PerUserLimit
+	}
+
+	if msg := validateTicketTier(&tier); msg != "" {
+		utils.ErrorResponse(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err := h.db.Create(&tier).Error; err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create ticket tier")
+		return
+	}
+
+	utils.SuccessResponse(w, ticketTierResponse(&tier, time.Now()))
+}
+
+// UpdateTier - Update a ticket tier (event organizer or admin)
+func (h *TicketTierHandler) UpdateTier(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.managedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTicketTierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var tier models.TicketTier
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the tier so the sold count checked against the new capacity cannot move underneath us
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND event_id = ?", mux.Vars(r)["tierId"], event.ID).First(&tier).Error; err != nil {
+			return err
+		}
+
+		if req.Name != nil {
+			tier.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Description != nil {
+			tier.Description = req.Description
+		}
+		if req.Price != nil {
+			tier.Price = *req.Price
+		}
+		if req.Capacity != nil {
+			tier.Capacity = req.Capacity
+		}
+		if req.PerUserLimit != nil {
+			tier.PerUserLimit = *req.PerUserLimit
+		}
+		if req.SalesStartAt != nil {
+			tier.SalesStartAt = req.SalesStartAt
+		}
+		if req.SalesEndAt != nil {
+			tier.SalesEndAt = req.SalesEndAt
+		}
+		if req.SortOrder != nil {
+			tier.SortOrder = *req.SortOrder
+		}
+		if req.IsActive != nil {
+			tier.IsActive = *req.IsActive
+		}
+
+		if msg := validateTicketTier(&tier); msg != "" {
+			return errInvalidTicketTier{msg}
+		}
+
+		return tx.Save(&tier).Error
+	})
+
+	if err != nil {
+		var invalid errInvalidTicketTier
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, "Ticket tier not found")
+		case errors.As(err, &invalid):
+			utils.ErrorResponse(w, http.StatusBadRequest, invalid.reason)
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update ticket tier")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, ticketTierResponse(&tier, time.Now()))
+}
+
+// DeleteTier - Delete a ticket tier that has not sold any tickets (event organizer or admin)
+func (h *TicketTierHandler) DeleteTier(w http.ResponseWriter, r *http.Request) {
+	event, ok := h.managedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	result := h.db.Where("id = ? AND event_id = ? AND sold = 0", mux.Vars(r)["tierId"], event.ID).Delete(&models.TicketTier{})
+	if result.Error != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to delete ticket tier")
+		return
+	}
+	if result.RowsAffected == 0 {
+		var tier models.TicketTier
+		if err := h.db.Where("id = ? AND event_id = ?", mux.Vars(r)["tierId"], event.ID).First(&tier).Error; err == nil {
+			utils.ErrorResponse(w, http.StatusConflict, "Ticket tier has sold tickets; deactivate it instead")
+		} else {
+			utils.ErrorResponse(w, http.StatusNotFound, "Ticket tier not found")
+		}
+		return
+	}
+
+	utils.MessageResponse(w, "Ticket tier deleted successfully")
+}
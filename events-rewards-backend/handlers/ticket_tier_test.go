@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// createTestTier inserts an on-sale ticket tier for the event
+func createTestTier(t *testing.T, db *gorm.DB, event models.Event, capacity, perUserLimit int) models.TicketTier {
+	t.Helper()
+
+	tier := models.TicketTier{
+		ID:           uuid.New(),
+		EventID:      event.ID,
+		Name:         "General",
+		Price:        25,
+		Capacity:     &capacity,
+		PerUserLimit: perUserLimit,
+		IsActive:     true,
+	}
+	if err := db.Create(&tier).Error; err != nil {
+		t.Fatalf("create ticket tier: %v", err)
+	}
+	return tier
+}
+
+// registerForTier books quantity tickets from the tier as user, returning the status code
+func registerForTier(h *EventHandler, event models.Event, tierID string, quantity int, user models.User) int {
+	body := strings.NewReader(`{"ticket_tier_id": "` + tierID + `", "quantity": ` + strconv.Itoa(quantity) + `}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/"+event.ID.String()+"/register", body)
+	req = withUser(mux.SetURLVars(req, map[string]string{"id": event.ID.String()}), user)
+	rec := httptest.NewRecorder()
+	h.RegisterForEvent(rec, req)
+	return rec.Code
+}
+
+func TestRegisterForTicketTierLimits(t *testing.T) {
+	db := openTestDB(t)
+	bus := services.NewEventBus()
+	h := NewEventHandler(db, bus, services.NewWaitlistService(db, bus))
+
+	event := createTestEvent(t, db, 10)
+	tier := createTestTier(t, db, event, 3, 2)
+	first, second := createTestUser(t, db, nil), createTestUser(t, db, nil)
+
+	sold := func() int {
+		t.Helper()
+		var reloaded models.TicketTier
+		if err := db.First(&reloaded, "id = ?", tier.ID).Error; err != nil {
+			t.Fatalf("reload ticket tier: %v", err)
+		}
+		return reloaded.Sold
+	}
+	participants := func() int {
+		t.Helper()
+		var reloaded models.Event
+		if err := db.First(&reloaded, "id = ?", event.ID).Error; err != nil {
+			t.Fatalf("reload event: %v", err)
+		}
+		return reloaded.CurrentParticipants
+	}
+
+	tests := []struct {
+		name     string
+		user     models.User
+		tierID   string
+		quantity int
+		want     int
+	}{
+		{"no tier for a tiered event", first, "", 1, http.StatusBadRequest},
+		{"unknown tier", first, uuid.New().String(), 1, http.StatusNotFound},
+		{"over the per-user limit", first, tier.ID.String(), 3, http.StatusBadRequest},
+		{"within the limit", first, tier.ID.String(), 2, http.StatusOK},
+		{"more than remain", second, tier.ID.String(), 2, http.StatusConflict},
+		{"last ticket", second, tier.ID.String(), 1, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := registerForTier(h, event, tt.tierID, tt.quantity, tt.user); code != tt.want {
+			t.Errorf("%s: register returned %d, want %d", tt.name, code, tt.want)
+		}
+	}
+
+	if got := sold(); got != 3 {
+		t.Errorf("tier sold %d tickets, want 3", got)
+	}
+	if got := participants(); got != 3 {
+		t.Errorf("event has %d participants, want 3", got)
+	}
+	if registration := loadRegistration(t, db, event, first); registration.Quantity != 2 {
+		t.Errorf("registration holds %d tickets, want 2", registration.Quantity)
+	}
+
+	// Unregistering puts the tickets back on sale rather than offering them to a waitlist
+	rec := httptest.NewRecorder()
+	h.UnregisterFromEvent(rec, eventRequest(http.MethodDelete, event, first))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unregister returned %d, want 200", rec.Code)
+	}
+	if got := sold(); got != 1 {
+		t.Errorf("tier sold %d tickets after unregistering, want 1", got)
+	}
+	if got := participants(); got != 1 {
+		t.Errorf("event has %d participants after unregistering, want 1", got)
+	}
+}
+
+func TestRegisterForTicketTierSaleWindow(t *testing.T) {
+	db := openTestDB(t)
+	bus := services.NewEventBus()
+	h := NewEventHandler(db, bus, services.NewWaitlistService(db, bus))
+
+	event := createTestEvent(t, db, 10)
+	upcoming := createTestTier(t, db, event, 5, 1)
+	ended := createTestTier(t, db, event, 5, 1)
+	tomorrow, yesterday := time.Now().Add(24*time.Hour), time.Now().Add(-24*time.Hour)
+	db.Model(&upcoming).Update("sales_start_at", tomorrow)
+	db.Model(&ended).Update("sales_end_at", yesterday)
+
+	user := createTestUser(t, db, nil)
+	for name, tier := range map[string]models.TicketTier{"before the sale": upcoming, "after the sale": ended} {
+		if code := registerForTier(h, event, tier.ID.String(), 1, user); code != http.StatusBadRequest {
+			t.Errorf("%s: register returned %d, want 400", name, code)
+		}
+	}
+
+	var registrations int64
+	db.Model(&models.EventRegistration{}).Where("event_id = ?", event.ID).Count(&registrations)
+	if registrations != 0 {
+		t.Errorf("refused registrations left %d rows behind", registrations)
+	}
+}
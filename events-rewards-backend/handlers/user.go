@@ -58,22 +58,23 @@ func (h *UserHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Count different types of rewards
-	var totalRewards, pendingRewards, claimedRewards int64
+	var totalRewards, pendingRewards, claimedRewards, expiredRewards int64
 
 	h.db.Model(&models.UserReward{}).Where("user_id = ?", userID).Count(&totalRewards)
 	h.db.Model(&models.UserReward{}).Where("user_id = ? AND status = ?", userID, "pending").Count(&pendingRewards)
 	h.db.Model(&models.UserReward{}).Where("user_id = ? AND status = ?", userID, "claimed").Count(&claimedRewards)
+	h.db.Model(&models.UserReward{}).Where("user_id = ? AND status = ?", userID, "expired").Count(&expiredRewards)
 
-	// Count total spins (from spin attempts)
+	// Count total spins (one spin event per spin)
 	var totalSpins int64
-	h.db.Model(&models.SpinAttempt{}).Where("user_id = ?", userID).
-		Select("COALESCE(SUM(attempts_count), 0)").Scan(&totalSpins)
+	h.db.Model(&models.SpinEvent{}).Where("user_id = ?", userID).Count(&totalSpins)
 
 	stats := map[string]interface{}{
 		"total_spins":     totalSpins,
 		"total_wins":      totalRewards,
 		"pending_rewards": pendingRewards,
 		"claimed_rewards": claimedRewards,
+		"expired_rewards": expiredRewards,
 	}
 
 	utils.SuccessResponse(w, stats)
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/Hritikpandey-ops/events-rewards-backend/services"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// eventRequest builds a request for an /events/{id}/... route as user
+func eventRequest(method string, event models.Event, user models.User) *http.Request {
+	req := httptest.NewRequest(method, "/api/v1/events/"+event.ID.String(), nil)
+	return withUser(mux.SetURLVars(req, map[string]string{"id": event.ID.String()}), user)
+}
+
+// loadRegistration returns the user's registration for the event
+func loadRegistration(t *testing.T, db *gorm.DB, event models.Event, user models.User) models.EventRegistration {
+	t.Helper()
+
+	var registration models.EventRegistration
+	if err := db.Where("user_id = ? AND event_id = ?", user.ID, event.ID).First(&registration).Error; err != nil {
+		t.Fatalf("load registration: %v", err)
+	}
+	return registration
+}
+
+// addToWaitlist queues the user for the event as if they had registered at registeredAt
+func addToWaitlist(t *testing.T, db *gorm.DB, event models.Event, user models.User, registeredAt time.Time) {
+	t.Helper()
+
+	registration := models.EventRegistration{
+		ID:               uuid.New(),
+		UserID:           user.ID,
+		EventID:          event.ID,
+		RegistrationDate: registeredAt,
+		Status:           models.RegistrationStatusWaitlisted,
+	}
+	if err := db.Create(&registration).Error; err != nil {
+		t.Fatalf("create waitlisted registration: %v", err)
+	}
+}
+
+func TestWaitlistPromotesWhenSeatIsReleased(t *testing.T) {
+	db := openTestDB(t)
+	bus := services.NewEventBus()
+	waitlist := services.NewWaitlistService(db, bus)
+	h := NewEventHandler(db, bus, waitlist)
+
+	event := createTestEvent(t, db, 1)
+	holder, first, second := createTestUser(t, db, nil), createTestUser(t, db, nil), createTestUser(t, db, nil)
+
+	for _, user := range []models.User{holder, first, second} {
+		rec := httptest.NewRecorder()
+		h.RegisterForEvent(rec, eventRequest(http.MethodPost, event, user))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("register returned %d, want 200", rec.Code)
+		}
+	}
+
+	if status := loadRegistration(t, db, event, first).Status; status != models.RegistrationStatusWaitlisted {
+		t.Fatalf("registration for a full event is %q, want %q", status, models.RegistrationStatusWaitlisted)
+	}
+	waiting := loadRegistration(t, db, event, second)
+	if position, err := waitlist.Position(&waiting); err != nil || position != 2 {
+		t.Errorf("second in line has position %d, %v, want 2", position, err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.UnregisterFromEvent(rec, eventRequest(http.MethodDelete, event, holder))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unregister returned %d, want 200", rec.Code)
+	}
+
+	// The longest-waiting user is offered the seat, which stays counted as taken
+	offered := loadRegistration(t, db, event, first)
+	if offered.Status != models.RegistrationStatusOffered || offered.ClaimExpiresAt == nil {
+		t.Fatalf("first in line is %q with claim expiry %v, want an open offer", offered.Status, offered.ClaimExpiresAt)
+	}
+	if status := loadRegistration(t, db, event, second).Status; status != models.RegistrationStatusWaitlisted {
+		t.Errorf("second in line is %q, want %q", status, models.RegistrationStatusWaitlisted)
+	}
+	var reloaded models.Event
+	db.First(&reloaded, "id = ?", event.ID)
+	if reloaded.CurrentParticipants != 1 {
+		t.Errorf("event has %d participants, want 1", reloaded.CurrentParticipants)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ConfirmWaitlistOffer(rec, eventRequest(http.MethodPost, event, first))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm returned %d, want 200", rec.Code)
+	}
+	if status := loadRegistration(t, db, event, first).Status; status != models.RegistrationStatusRegistered {
+		t.Errorf("confirmed registration is %q, want %q", status, models.RegistrationStatusRegistered)
+	}
+}
+
+func TestWaitlistExpireOffersPassesSeatOn(t *testing.T) {
+	db := openTestDB(t)
+	waitlist := services.NewWaitlistService(db, services.NewEventBus())
+
+	event := createTestEvent(t, db, 1)
+	db.Model(&models.Event{}).Where("id = ?", event.ID).Update("current_participants", 1)
+
+	lapsed, next := createTestUser(t, db, nil), createTestUser(t, db, nil)
+	expiredAt := time.Now().Add(-time.Minute)
+	offer := models.EventRegistration{
+		ID:               uuid.New(),
+		UserID:           lapsed.ID,
+		EventID:          event.ID,
+		RegistrationDate: time.Now().Add(-2 * time.Hour),
+		Status:           models.RegistrationStatusOffered,
+		ClaimExpiresAt:   &expiredAt,
+	}
+	if err := db.Create(&offer).Error; err != nil {
+		t.Fatalf("create offered registration: %v", err)
+	}
+	addToWaitlist(t, db, event, next, time.Now().Add(-time.Hour))
+
+	// Other tests may leave expired offers behind, so only this event's rows are checked
+	if _, err := waitlist.ExpireOffers(); err != nil {
+		t.Fatalf("ExpireOffers: %v", err)
+	}
+
+	var remaining int64
+	db.Model(&models.EventRegistration{}).Where("id = ?", offer.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Error("expired offer was not withdrawn")
+	}
+	if status := loadRegistration(t, db, event, next).Status; status != models.RegistrationStatusOffered {
+		t.Errorf("next in line is %q, want %q", status, models.RegistrationStatusOffered)
+	}
+	var reloaded models.Event
+	db.First(&reloaded, "id = ?", event.ID)
+	if reloaded.CurrentParticipants != 1 {
+		t.Errorf("event has %d participants, want 1", reloaded.CurrentParticipants)
+	}
+}
+
+func TestWaitlistFillOpenSeats(t *testing.T) {
+	db := openTestDB(t)
+	waitlist := services.NewWaitlistService(db, services.NewEventBus())
+
+	event := createTestEvent(t, db, 3)
+	db.Model(&models.Event{}).Where("id = ?", event.ID).Update("current_participants", 1)
+
+	now := time.Now()
+	users := []models.User{createTestUser(t, db, nil), createTestUser(t, db, nil), createTestUser(t, db, nil)}
+	for i, user := range users {
+		addToWaitlist(t, db, event, user, now.Add(time.Duration(i-3)*time.Hour))
+	}
+
+	var promoted []models.EventRegistration
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		promoted, err = waitlist.FillOpenSeats(tx, event.ID, now)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("FillOpenSeats: %v", err)
+	}
+	if len(promoted) != 2 {
+		t.Fatalf("%d users promoted, want 2", len(promoted))
+	}
+
+	// The two free seats go to the front of the queue
+	want := []string{models.RegistrationStatusOffered, models.RegistrationStatusOffered, models.RegistrationStatusWaitlisted}
+	for i, user := range users {
+		if status := loadRegistration(t, db, event, user).Status; status != want[i] {
+			t.Errorf("user %d in line is %q, want %q", i+1, status, want[i])
+		}
+	}
+	var reloaded models.Event
+	db.First(&reloaded, "id = ?", event.ID)
+	if reloaded.CurrentParticipants != 3 {
+		t.Errorf("event has %d participants, want 3", reloaded.CurrentParticipants)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/Hritikpandey-ops/events-rewards-backend/config"
 	"github.com/Hritikpandey-ops/events-rewards-backend/handlers"
@@ -34,12 +36,23 @@ func main() {
 	case "safe":
 		log.Println("Running safe migration with error handling...")
 		performSafeMigration(db)
+		migrateLegacySchema(db)
+		applySchemaExtras(db)
 	case "auto":
 		log.Println("Running automatic migration...")
 		performAutoMigration(db)
+		migrateLegacySchema(db)
+		applySchemaExtras(db)
 	default:
 		log.Printf("Unknown migration mode '%s', defaulting to auto", migrationMode)
 		performAutoMigration(db)
+		migrateLegacySchema(db)
+		applySchemaExtras(db)
+	}
+
+	// Promote the configured bootstrap account so there is always a way in to the admin routes
+	if adminEmail := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); adminEmail != "" {
+		bootstrapAdmin(db, adminEmail)
 	}
 
 	// Initialize MinIO service
@@ -54,13 +67,61 @@ func main() {
 		log.Fatal("Failed to initialize MinIO service:", err)
 	}
 
+	// Initialize token blacklist and purge expired entries in the background
+	tokenBlacklist := services.NewTokenBlacklistService(db)
+	tokenBlacklist.StartSweeper(time.Hour)
+
+	// Initialize spin seeds and reveal finished periods in the background
+	spinSeeds := services.NewSpinSeedService(db)
+	spinSeeds.StartRevealer(time.Minute)
+
+	// Initialize the event bus and expire unclaimed rewards in the background
+	eventBus := services.NewEventBus()
+	rewardExpiry := services.NewRewardExpiryService(db, eventBus)
+	rewardExpiry.StartWorker(time.Minute)
+
+	// Credit points rewards claimed before the ledger existed; later runs find nothing to do
+	pointsLedger := services.NewPointsLedger(db)
+	if credited, err := pointsLedger.BackfillClaimedRewards(); err != nil {
+		log.Printf("Points backfill failed: %v", err)
+	} else if credited > 0 {
+		log.Printf("Backfilled points for %d claimed rewards", credited)
+	}
+
+	// Grant bonus spins when other parts of the app publish qualifying actions
+	bonusSpins := services.NewBonusSpinService(db)
+	bonusSpins.Subscribe(eventBus)
+
+	// Promote waitlisted users into freed seats and withdraw offers that are not confirmed in time
+	waitlist := services.NewWaitlistService(db, eventBus)
+	waitlist.StartWorker(time.Minute)
+
+	// Store first responses for Idempotency-Key retries and purge them once they expire
+	idempotencyKeys := services.NewIdempotencyService(db)
+	idempotencyKeys.StartSweeper(time.Hour)
+	idempotent := middleware.NewIdempotencyMiddleware(idempotencyKeys)
+
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(db, minioService)
-	eventHandler := handlers.NewEventHandler(db)
-	newsHandler := handlers.NewNewsHandler(db)
+	authHandler := handlers.NewAuthHandler(db, minioService, tokenBlacklist, eventBus)
+	eventHandler := handlers.NewEventHandler(db, eventBus, waitlist)
+	newsHandler := handlers.NewNewsHandler(db, eventBus)
 	uiConfigHandler := handlers.NewUIConfigHandler(db)
-	luckyDrawHandler := handlers.NewLuckyDrawHandler(db)
+	luckyDrawHandler := handlers.NewLuckyDrawHandler(db, spinSeeds, pointsLedger, bonusSpins, cfg.TrustedProxies)
 	userHandler := handlers.NewUserHandler(db)
+	adminHandler := handlers.NewAdminHandler(db)
+	rewardHandler := handlers.NewRewardHandler(db)
+	spinPolicyHandler := handlers.NewSpinPolicyHandler(db)
+	campaignHandler := handlers.NewCampaignHandler(db)
+	fairnessHandler := handlers.NewFairnessHandler(db, spinSeeds)
+	pointsHandler := handlers.NewPointsHandler(db, pointsLedger)
+	catalogHandler := handlers.NewCatalogHandler(db, pointsLedger, eventBus, bonusSpins)
+	bonusSpinHandler := handlers.NewBonusSpinHandler(db, bonusSpins)
+	eventSeriesHandler := handlers.NewEventSeriesHandler(db)
+	ticketTierHandler := handlers.NewTicketTierHandler(db)
+	calendarHandler := handlers.NewCalendarHandler(db)
+	searchHandler := handlers.NewSearchHandler(db)
+	checkInHandler := handlers.NewCheckInHandler(db, services.NewTicketTokenService(cfg.JWTSecret), eventBus)
+	redemptionHandler := handlers.NewRedemptionHandler(db, services.NewRedemptionTokenService(cfg.JWTSecret), pointsLedger)
 
 	// Setup router
 	r := mux.NewRouter()
@@ -78,8 +139,10 @@ func main() {
 	api := r.PathPrefix("/api/v1").Subrouter()
 
 	// Public routes (no authentication required) - NOW WITH OPTIONS SUPPORT
+	// Auth routes are not idempotent: a stored response would keep the issued tokens
 	api.HandleFunc("/auth/register", authHandler.Register).Methods("POST", "OPTIONS")
 	api.HandleFunc("/auth/login", authHandler.Login).Methods("POST", "OPTIONS")
+	api.HandleFunc("/auth/refresh", authHandler.RefreshToken).Methods("POST", "OPTIONS")
 
 	// Public news routes
 	api.HandleFunc("/news", newsHandler.GetNews).Methods("GET", "OPTIONS")
@@ -90,10 +153,25 @@ func main() {
 	// Public events routes
 	api.HandleFunc("/events", eventHandler.GetEvents).Methods("GET", "OPTIONS")
 	api.HandleFunc("/events/{id}", eventHandler.GetEvent).Methods("GET", "OPTIONS")
+	api.HandleFunc("/events/{id}/tiers", ticketTierHandler.ListTiers).Methods("GET", "OPTIONS")
+	api.HandleFunc("/event-series/{id}", eventSeriesHandler.GetSeries).Methods("GET", "OPTIONS")
+
+	// Calendar export routes (public; personal feeds are authenticated by their token)
+	api.HandleFunc("/events/{id}/calendar.ics", calendarHandler.GetEventCalendar).Methods("GET", "OPTIONS")
+	api.HandleFunc("/calendar/categories/{category}.ics", calendarHandler.GetCategoryCalendar).Methods("GET", "OPTIONS")
+	api.HandleFunc("/calendar/feeds/{token}.ics", calendarHandler.GetUserCalendar).Methods("GET", "OPTIONS")
+
+	// Public lucky draw fairness routes
+	api.HandleFunc("/lucky-draw/fairness/current", fairnessHandler.GetCurrentSeed).Methods("GET", "OPTIONS")
+	api.HandleFunc("/lucky-draw/fairness/seeds", fairnessHandler.ListRevealedSeeds).Methods("GET", "OPTIONS")
+	api.HandleFunc("/lucky-draw/fairness/verify", fairnessHandler.VerifySpin).Methods("POST", "OPTIONS")
 
 	// Public UI config routes
 	api.HandleFunc("/config/ui", uiConfigHandler.GetConfig).Methods("GET", "OPTIONS")
 
+	// Search route (public)
+	api.HandleFunc("/search", searchHandler.Search).Methods("GET", "OPTIONS")
+
 	// Health check endpoint
 	api.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -106,7 +184,8 @@ func main() {
 
 	// Protected routes (require authentication)
 	protected := api.NewRoute().Subrouter()
-	protected.Use(middleware.AuthMiddleware)
+	protected.Use(middleware.NewAuthMiddleware(tokenBlacklist))
+	protected.Use(idempotent)
 
 	// Auth routes (protected) - WITH OPTIONS SUPPORT
 	protected.HandleFunc("/auth/verify-identity", authHandler.VerifyIdentity).Methods("POST", "OPTIONS")
@@ -120,20 +199,38 @@ func main() {
 	protected.HandleFunc("/user/news", newsHandler.GetMyNews).Methods("GET", "OPTIONS")
 
 	// Event routes (protected) - WITH OPTIONS SUPPORT
-	protected.HandleFunc("/events", eventHandler.CreateEvent).Methods("POST", "OPTIONS")
 	protected.HandleFunc("/events", eventHandler.GetEvents).Methods("GET", "OPTIONS")
-	protected.HandleFunc("/events/{id}", eventHandler.UpdateEvent).Methods("PUT", "OPTIONS")
-	protected.HandleFunc("/events/{id}", eventHandler.DeleteEvent).Methods("DELETE", "OPTIONS")
 	protected.HandleFunc("/events/{id}/register", eventHandler.RegisterForEvent).Methods("POST", "OPTIONS")
 	protected.HandleFunc("/events/{id}/unregister", eventHandler.UnregisterFromEvent).Methods("DELETE", "OPTIONS")
+	protected.HandleFunc("/events/{id}/waitlist/confirm", eventHandler.ConfirmWaitlistOffer).Methods("POST", "OPTIONS")
+	protected.HandleFunc("/events/{id}/ticket", checkInHandler.GetTicket).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/events/{id}/ticket/qr", checkInHandler.GetTicketQRCode).Methods("GET", "OPTIONS")
 	protected.HandleFunc("/events/my-events", eventHandler.GetUserRegistrations).Methods("GET", "OPTIONS")
 
-	// News routes (protected - for admin/content management) - WITH OPTIONS SUPPORT
-	protected.HandleFunc("/news", newsHandler.CreateNews).Methods("POST", "OPTIONS")
-	protected.HandleFunc("/news/{id}", newsHandler.UpdateNews).Methods("PUT", "OPTIONS")
-	protected.HandleFunc("/news/{id}", newsHandler.DeleteNews).Methods("DELETE", "OPTIONS")
-	protected.HandleFunc("/news/{id}/toggle-publish", newsHandler.TogglePublishStatus).Methods("PATCH", "OPTIONS")
+	// Event management routes (organizers and admins)
+	eventManagement := protected.NewRoute().Subrouter()
+	eventManagement.Use(middleware.RequireRole(models.RoleOrganizer))
+	eventManagement.HandleFunc("/events", eventHandler.CreateEvent).Methods("POST", "OPTIONS")
+	eventManagement.HandleFunc("/event-series", eventSeriesHandler.CreateSeries).Methods("POST", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}", eventHandler.UpdateEvent).Methods("PUT", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}", eventHandler.DeleteEvent).Methods("DELETE", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}/tiers", ticketTierHandler.CreateTier).Methods("POST", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}/tiers/{tierId}", ticketTierHandler.UpdateTier).Methods("PUT", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}/tiers/{tierId}", ticketTierHandler.DeleteTier).Methods("DELETE", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}/check-in", checkInHandler.CheckIn).Methods("POST", "OPTIONS")
+	eventManagement.HandleFunc("/events/{id}/attendance", checkInHandler.GetAttendance).Methods("GET", "OPTIONS")
+
+	// News routes (protected) - WITH OPTIONS SUPPORT
 	protected.HandleFunc("/news/{id}/bookmark", newsHandler.BookmarkNews).Methods("POST", "OPTIONS")
+	protected.HandleFunc("/news/{id}/read", newsHandler.MarkNewsRead).Methods("POST", "OPTIONS")
+
+	// News management routes (editors and admins)
+	newsManagement := protected.NewRoute().Subrouter()
+	newsManagement.Use(middleware.RequireRole(models.RoleEditor))
+	newsManagement.HandleFunc("/news", newsHandler.CreateNews).Methods("POST", "OPTIONS")
+	newsManagement.HandleFunc("/news/{id}", newsHandler.UpdateNews).Methods("PUT", "OPTIONS")
+	newsManagement.HandleFunc("/news/{id}", newsHandler.DeleteNews).Methods("DELETE", "OPTIONS")
+	newsManagement.HandleFunc("/news/{id}/toggle-publish", newsHandler.TogglePublishStatus).Methods("PATCH", "OPTIONS")
 
 	// UI Config routes (protected) - WITH OPTIONS SUPPORT
 	protected.HandleFunc("/ui-config", uiConfigHandler.CreateConfig).Methods("POST", "OPTIONS")
@@ -144,15 +241,78 @@ func main() {
 	protected.HandleFunc("/lucky-draw/spin", luckyDrawHandler.Spin).Methods("POST", "OPTIONS")
 	protected.HandleFunc("/lucky-draw/remaining-spins", luckyDrawHandler.GetRemainingSpins).Methods("GET", "OPTIONS")
 	protected.HandleFunc("/lucky-draw/claim", luckyDrawHandler.ClaimReward).Methods("POST", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/rewards", luckyDrawHandler.GetRewards).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/my-rewards", luckyDrawHandler.GetUserRewards).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/history", luckyDrawHandler.GetSpinHistory).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/stats", luckyDrawHandler.GetUserStats).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/campaigns", campaignHandler.GetActiveCampaigns).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/spins/{id}/proof", fairnessHandler.GetSpinProof).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/rewards/{id}/qr", redemptionHandler.GetRewardQRCode).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/lucky-draw/bonus-spins", bonusSpinHandler.GetMyBonusSpins).Methods("GET", "OPTIONS")
+
+	// Counter redemption routes (staff only)
+	staff := protected.PathPrefix("/staff").Subrouter()
+	staff.Use(middleware.RequireRole(models.RoleStaff))
+	staff.HandleFunc("/redeem", redemptionHandler.RedeemReward).Methods("POST", "OPTIONS")
+
+	// Admin routes
+	admin := protected.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	admin.HandleFunc("/users/{id}/role", adminHandler.UpdateUserRole).Methods("PUT", "OPTIONS")
+	admin.HandleFunc("/users/{id}/tier", adminHandler.UpdateUserTier).Methods("PUT", "OPTIONS")
+	admin.HandleFunc("/spin-events", adminHandler.ListSpinEvents).Methods("GET", "OPTIONS")
+
+	// Reward catalog management (admin only)
+	admin.HandleFunc("/rewards", rewardHandler.ListRewards).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/rewards", rewardHandler.CreateReward).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/rewards/{id}", rewardHandler.GetReward).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/rewards/{id}", rewardHandler.UpdateReward).Methods("PUT", "OPTIONS")
+	admin.HandleFunc("/rewards/{id}", rewardHandler.DeleteReward).Methods("DELETE", "OPTIONS")
+	admin.HandleFunc("/rewards/{id}/history", rewardHandler.GetRewardHistory).Methods("GET", "OPTIONS")
+
+	// Spin policy management (admin only)
+	admin.HandleFunc("/spin-policies", spinPolicyHandler.ListPolicies).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/spin-policies", spinPolicyHandler.CreatePolicy).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/spin-policies/{id}", spinPolicyHandler.UpdatePolicy).Methods("PUT", "OPTIONS")
+
+	// Bonus spin rules (admin only)
+	admin.HandleFunc("/bonus-spin-rules", bonusSpinHandler.ListRules).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/bonus-spin-rules/{action}", bonusSpinHandler.UpdateRule).Methods("PUT", "OPTIONS")
+
+	// Points ledger reconciliation (admin only)
+	admin.HandleFunc("/points/reconcile", pointsHandler.Reconcile).Methods("GET", "OPTIONS")
+
+	// Points catalog and order management (admin only)
+	admin.HandleFunc("/catalog", catalogHandler.ListItems).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/catalog", catalogHandler.CreateItem).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/catalog/{id}", catalogHandler.UpdateItem).Methods("PUT", "OPTIONS")
+	admin.HandleFunc("/orders", catalogHandler.ListOrders).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/orders/{id}/status", catalogHandler.UpdateOrderStatus).Methods("PUT", "OPTIONS")
+
+	// Lucky draw campaign management (admin only)
+	admin.HandleFunc("/campaigns", campaignHandler.ListCampaigns).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/campaigns", campaignHandler.CreateCampaign).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/campaigns/{id}", campaignHandler.GetCampaign).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/campaigns/{id}", campaignHandler.UpdateCampaign).Methods("PUT", "OPTIONS")
+	admin.HandleFunc("/campaigns/{id}", campaignHandler.DeleteCampaign).Methods("DELETE", "OPTIONS")
+	admin.HandleFunc("/campaigns/{id}/rewards", campaignHandler.SetCampaignRewards).Methods("PUT", "OPTIONS")
 
 	// User reward routes for retrieving rewards and stats - WITH OPTIONS SUPPORT
 
 	protected.HandleFunc("/user/events", eventHandler.GetUserEvents).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/user/events/nearby", eventHandler.GetNearbyEvents).Methods("GET", "OPTIONS")
 	protected.HandleFunc("/user/rewards", userHandler.GetUserRewards).Methods("GET", "OPTIONS")
 	protected.HandleFunc("/user/stats", userHandler.GetUserStats).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/user/points", pointsHandler.GetBalance).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/user/points/history", pointsHandler.GetHistory).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/catalog", catalogHandler.GetCatalog).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/user/orders", catalogHandler.GetMyOrders).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/user/orders", catalogHandler.PlaceOrder).Methods("POST", "OPTIONS")
 	protected.HandleFunc("/user/profile", authHandler.UpdateProfile).Methods("PUT", "OPTIONS")
 	protected.HandleFunc("/user/device-info", authHandler.UpdateDeviceInfo).Methods("PUT", "OPTIONS")
 	protected.HandleFunc("/user/location", authHandler.UpdateLocation).Methods("PUT", "OPTIONS")
+	protected.HandleFunc("/user/calendar-feed", calendarHandler.GetCalendarFeed).Methods("GET", "OPTIONS")
+	protected.HandleFunc("/user/calendar-feed/reset", calendarHandler.ResetCalendarFeed).Methods("POST", "OPTIONS")
 
 	// Start server
 	log.Printf("Server starting on port %s", cfg.Port)
@@ -172,7 +332,28 @@ func performAutoMigration(db *gorm.DB) {
 		&models.Reward{},
 		&models.UserReward{},
 		&models.SpinAttempt{},
+		&models.SpinEvent{},
 		&models.TokenBlacklist{},
+		&models.RefreshToken{},
+		&models.RewardWeightChange{},
+		&models.SpinPolicy{},
+		&models.Campaign{},
+		&models.CampaignReward{},
+		&models.CampaignSpinAttempt{},
+		&models.SpinSeed{},
+		&models.SpinProof{},
+		&models.PointsAccount{},
+		&models.PointsTransaction{},
+		&models.PointsEntry{},
+		&models.CatalogItem{},
+		&models.CatalogOrder{},
+		&models.IdempotencyKey{},
+		&models.BonusSpinRule{},
+		&models.BonusSpinGrant{},
+		&models.NewsRead{},
+		&models.TicketTier{},
+		&models.EventSeries{},
+		&models.CalendarFeed{},
 	)
 
 	if err != nil {
@@ -193,7 +374,28 @@ func performSafeMigration(db *gorm.DB) {
 		&models.Reward{},
 		&models.UserReward{},
 		&models.SpinAttempt{},
+		&models.SpinEvent{},
 		&models.TokenBlacklist{},
+		&models.RefreshToken{},
+		&models.RewardWeightChange{},
+		&models.SpinPolicy{},
+		&models.Campaign{},
+		&models.CampaignReward{},
+		&models.CampaignSpinAttempt{},
+		&models.SpinSeed{},
+		&models.SpinProof{},
+		&models.PointsAccount{},
+		&models.PointsTransaction{},
+		&models.PointsEntry{},
+		&models.CatalogItem{},
+		&models.CatalogOrder{},
+		&models.IdempotencyKey{},
+		&models.BonusSpinRule{},
+		&models.BonusSpinGrant{},
+		&models.NewsRead{},
+		&models.TicketTier{},
+		&models.EventSeries{},
+		&models.CalendarFeed{},
 	}
 
 	for _, model := range models {
@@ -207,3 +409,70 @@ func performSafeMigration(db *gorm.DB) {
 
 	log.Println("Safe migration completed")
 }
+
+// bootstrapAdmin grants the admin role to the user with the given email, if they exist
+func bootstrapAdmin(db *gorm.DB, email string) {
+	result := db.Model(&models.User{}).
+		Where("email = ? AND role <> ?", strings.ToLower(email), models.RoleAdmin).
+		Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		log.Printf("Failed to bootstrap admin %s: %v", email, result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Printf("Granted admin role to %s", email)
+	}
+}
+
+// migrateLegacySchema cleans up columns that AutoMigrate leaves behind when a model changes shape
+func migrateLegacySchema(db *gorm.DB) {
+	// token_blacklist used to be keyed on the raw token; it is keyed on jti now
+	if db.Migrator().HasColumn(&models.TokenBlacklist{}, "token") {
+		if err := db.Migrator().DropColumn(&models.TokenBlacklist{}, "token"); err != nil {
+			log.Printf("Migration warning: could not drop token_blacklist.token: %v", err)
+		}
+	}
+}
+
+// applySchemaExtras installs database objects AutoMigrate cannot express, such as triggers and generated columns
+func applySchemaExtras(db *gorm.DB) {
+	statements := []string{
+		// One registration per user and event. Created here rather than through a
+		// model tag so existing duplicates log a warning instead of failing startup.
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_event_registrations_user_event
+			ON event_registrations (user_id, event_id)`,
+
+		// spin_events is an audit log: rows may be inserted but never changed or removed
+		`CREATE OR REPLACE FUNCTION spin_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'spin_events is append-only';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS spin_events_no_modify ON spin_events`,
+		`CREATE TRIGGER spin_events_no_modify BEFORE UPDATE OR DELETE ON spin_events
+			FOR EACH ROW EXECUTE FUNCTION spin_events_append_only()`,
+		`DROP TRIGGER IF EXISTS spin_events_no_truncate ON spin_events`,
+		`CREATE TRIGGER spin_events_no_truncate BEFORE TRUNCATE ON spin_events
+			FOR EACH STATEMENT EXECUTE FUNCTION spin_events_append_only()`,
+
+		// Full-text search vectors, kept current by Postgres as generated columns.
+		// Weights rank title matches above descriptions and body text.
+		`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(category, '') || ' ' || coalesce(venue, '') || ' ' || coalesce(location, '')), 'C')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING GIN (search_vector)`,
+		`ALTER TABLE news ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(summary, '')), 'B') ||
+			setweight(to_tsvector('english', coalesce(content, '')), 'C') ||
+			setweight(to_tsvector('english', coalesce(category, '')), 'D')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_news_search_vector ON news USING GIN (search_vector)`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Printf("Migration warning: could not apply schema extra: %v", err)
+		}
+	}
+}
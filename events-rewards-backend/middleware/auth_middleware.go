@@ -14,11 +14,24 @@ type Claims struct {
 	UserID   string `json:"user_id"`
 	Email    string `json:"email"`
 	DeviceID string `json:"device_id"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// AuthMiddleware is a middleware function that validates JWT tokens
-func AuthMiddleware(next http.Handler) http.Handler {
+// TokenRevocationChecker reports whether a token has been revoked by its jti
+type TokenRevocationChecker interface {
+	IsRevoked(jti string) bool
+}
+
+// NewAuthMiddleware returns a middleware function that validates JWT tokens
+// and rejects tokens that have been revoked through logout
+func NewAuthMiddleware(revocations TokenRevocationChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authMiddleware(next, revocations)
+	}
+}
+
+func authMiddleware(next http.Handler, revocations TokenRevocationChecker) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set content type for error responses
 		w.Header().Set("Content-Type", "application/json")
@@ -68,10 +81,18 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Tokens without a jti cannot be revoked, so they are not accepted
+		if claims.ID == "" || revocations.IsRevoked(claims.ID) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success": false, "error": "Token has been revoked"}`))
+			return
+		}
+
 		// Add user info to context
 		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
 		ctx = context.WithValue(ctx, "email", claims.Email)
 		ctx = context.WithValue(ctx, "device_id", claims.DeviceID)
+		ctx = context.WithValue(ctx, "role", claims.Role)
 
 		// Call the next handler with the updated context
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -95,3 +116,9 @@ func GetDeviceIDFromContext(r *http.Request) (string, bool) {
 	deviceID, ok := r.Context().Value("device_id").(string)
 	return deviceID, ok
 }
+
+// GetRoleFromContext retrieves the user's role from request context
+func GetRoleFromContext(r *http.Request) (string, bool) {
+	role, ok := r.Context().Value("role").(string)
+	return role, ok
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	maxIdempotencyKeyLength = 255
+	// maxReplayBodySize bounds the response size stored for replay
+	maxReplayBodySize = 1 << 20
+	// maxIdempotentRequestSize bounds the request body read into memory to fingerprint it
+	maxIdempotentRequestSize = 1 << 20
+)
+
+// IdempotencyStore keeps the first response for each idempotency key
+type IdempotencyStore interface {
+	Reserve(scope, key, fingerprint string) (*models.IdempotencyKey, bool, error)
+	Complete(scope, key string, statusCode int, contentType string, body []byte) error
+	Release(scope, key string) error
+}
+
+// NewIdempotencyMiddleware returns a middleware that replays the stored
+// response when a state-changing request is retried with the same
+// Idempotency-Key. Requests without the header pass straight through, as do
+// multipart uploads, which are too large to buffer and fingerprint. On
+// protected routes it must run after the auth middleware so keys are scoped per user.
+func NewIdempotencyMiddleware(store IdempotencyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isStateChanging(r.Method) || isMultipart(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+
+			if len(key) > maxIdempotencyKeyLength {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"success": false, "error": "Idempotency-Key is too long"}`))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestSize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+					w.Write([]byte(`{"success": false, "error": "Request body is too large"}`))
+					return
+				}
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"success": false, "error": "Failed to read request body"}`))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := "anonymous"
+			if userID, ok := GetUserIDFromContext(r); ok {
+				scope = userID
+			}
+
+			fingerprint := requestFingerprint(r, body)
+			record, reserved, err := store.Reserve(scope, key, fingerprint)
+			if err != nil {
+				log.Printf("Idempotency key lookup failed: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"success": false, "error": "Failed to process request"}`))
+				return
+			}
+
+			if !reserved {
+				switch {
+				case record.Fingerprint != fingerprint:
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					w.Write([]byte(`{"success": false, "error": "Idempotency-Key was already used for a different request"}`))
+				case !record.Completed:
+					w.WriteHeader(http.StatusConflict)
+					w.Write([]byte(`{"success": false, "error": "A request with this Idempotency-Key is still being processed"}`))
+				default:
+					if record.ContentType != "" {
+						w.Header().Set("Content-Type", record.ContentType)
+					}
+					w.Header().Set("Idempotent-Replayed", "true")
+					w.WriteHeader(record.StatusCode)
+					w.Write(record.Body)
+				}
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// Server errors and oversized responses are not stored, so the client can retry them
+			if recorder.statusCode >= http.StatusInternalServerError || recorder.overflow {
+				if err := store.Release(scope, key); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+				return
+			}
+
+			// A key whose response could not be stored is released, or every retry would be told it is still in progress
+			if err := store.Complete(scope, key, recorder.statusCode, w.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+				log.Printf("Failed to store idempotent response: %v", err)
+				if err := store.Release(scope, key); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func isStateChanging(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isMultipart(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/")
+}
+
+// requestFingerprint identifies the request a key was first used with
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes the response through while keeping a copy for replay
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	if !rec.wroteHeader {
+		rec.statusCode = statusCode
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	rec.wroteHeader = true
+	if !rec.overflow {
+		if rec.body.Len()+len(data) > maxReplayBodySize {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(data)
+		}
+	}
+	return rec.ResponseWriter.Write(data)
+}
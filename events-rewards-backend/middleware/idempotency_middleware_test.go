@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+)
+
+// memoryIdempotencyStore is an in-memory IdempotencyStore for tests
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyKey
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*models.IdempotencyKey)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(scope, key, fingerprint string) (*models.IdempotencyKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[scope+"/"+key]; ok {
+		copied := *existing
+		return &copied, false, nil
+	}
+	record := &models.IdempotencyKey{Scope: scope, Key: key, Fingerprint: fingerprint}
+	s.records[scope+"/"+key] = record
+	copied := *record
+	return &copied, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(scope, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := s.records[scope+"/"+key]
+	record.Completed = true
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = append([]byte(nil), body...)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[scope+"/"+key]; ok && !record.Completed {
+		delete(s.records, scope+"/"+key)
+	}
+	return nil
+}
+
+func idempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/user/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestIdempotencyReplaysFirstResponse(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyMiddleware(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"order": 1}`))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("key-1", `{"item_id": "a"}`))
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, idempotentRequest("key-1", `{"item_id": "a"}`))
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"order": 1}` {
+		t.Errorf("retry got %d %q, want 201 %q", retry.Code, retry.Body.String(), `{"order": 1}`)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("retry is not marked as replayed")
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("first response is marked as replayed")
+	}
+}
+
+func TestIdempotencyRejectsDifferentRequestWithSameKey(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyMiddleware(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("key-1", `{"item_id": "a"}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest("key-1", `{"item_id": "b"}`))
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key returned %d, want 422", rec.Code)
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyConflictsWhileInFlight(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handler := NewIdempotencyMiddleware(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("key-1", `{"item_id": "a"}`))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, idempotentRequest("key-1", `{"item_id": "a"}`))
+	close(finish)
+	<-done
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("retry while in flight returned %d, want 409", rec.Code)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyMiddleware(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	failed := httptest.NewRecorder()
+	handler.ServeHTTP(failed, idempotentRequest("key-1", `{"item_id": "a"}`))
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, idempotentRequest("key-1", `{"item_id": "a"}`))
+
+	if failed.Code != http.StatusInternalServerError {
+		t.Fatalf("first request returned %d, want 500", failed.Code)
+	}
+	if calls != 2 || retry.Code != http.StatusOK {
+		t.Errorf("retry after a server error ran the handler %d times with status %d, want 2 and 200", calls, retry.Code)
+	}
+}
+
+func TestIdempotencyPassesThroughWithoutKey(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyMiddleware(newMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("", `{"item_id": "a"}`))
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times without a key, want 2", calls)
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+)
+
+// RequireRole allows the request through only if the authenticated user has one
+// of the given roles. Admins are always allowed. Must run after the auth middleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(roles)+1)
+	for _, role := range roles {
+		allowed[role] = true
+	}
+	allowed[models.RoleAdmin] = true
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := GetRoleFromContext(r)
+			if !ok || !allowed[role] {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"success": false, "error": "You do not have permission to perform this action"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsAdmin reports whether the authenticated user is an admin
+func IsAdmin(r *http.Request) bool {
+	role, _ := GetRoleFromContext(r)
+	return role == models.RoleAdmin
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions that can earn bonus spins
+const (
+	BonusActionEventRegistration    = "event_registration"
+	BonusActionEventCheckIn         = "event_check_in"
+	BonusActionIdentityVerification = "identity_verification"
+	BonusActionNewsRead             = "news_read"
+	BonusActionExtraSpinPurchase    = "extra_spin_purchase"
+)
+
+// IsValidBonusAction reports whether action is one of the actions that can earn bonus spins
+func IsValidBonusAction(action string) bool {
+	switch action {
+	case BonusActionEventRegistration, BonusActionEventCheckIn, BonusActionIdentityVerification,
+		BonusActionNewsRead, BonusActionExtraSpinPurchase:
+		return true
+	}
+	return false
+}
+
+// BonusSpinRule configures how many spins an action earns and how often it
+// can earn them. DailyCap and TotalCap count grants per user; nil means no cap.
+type BonusSpinRule struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Action       string     `json:"action" gorm:"type:varchar(40);not null;uniqueIndex"`
+	SpinsGranted int        `json:"spins_granted" gorm:"not null;default:1"`
+	DailyCap     *int       `json:"daily_cap"`
+	TotalCap     *int       `json:"total_cap"`
+	IsActive     bool       `json:"is_active" gorm:"not null"`
+	UpdatedBy    *uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for BonusSpinRule model
+func (BonusSpinRule) TableName() string {
+	return "bonus_spin_rules"
+}
+
+// DefaultBonusSpinRules returns the built-in rules used for actions an admin has not configured
+func DefaultBonusSpinRules() map[string]BonusSpinRule {
+	intPtr := func(value int) *int { return &value }
+
+	return map[string]BonusSpinRule{
+		BonusActionEventRegistration:    {Action: BonusActionEventRegistration, SpinsGranted: 1, DailyCap: intPtr(2), IsActive: true},
+		BonusActionEventCheckIn:         {Action: BonusActionEventCheckIn, SpinsGranted: 2, DailyCap: intPtr(2), IsActive: true},
+		BonusActionIdentityVerification: {Action: BonusActionIdentityVerification, SpinsGranted: 3, TotalCap: intPtr(1), IsActive: true},
+		BonusActionNewsRead:             {Action: BonusActionNewsRead, SpinsGranted: 1, DailyCap: intPtr(3), IsActive: true},
+		BonusActionExtraSpinPurchase:    {Action: BonusActionExtraSpinPurchase, SpinsGranted: 1, IsActive: true},
+	}
+}
+
+// BonusSpinGrant records spins earned by one action. The same action and
+// reference can only pay out once per user.
+type BonusSpinGrant struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_bonus_spin_grants_user_action_reference;index"`
+	Action      string    `json:"action" gorm:"type:varchar(40);not null;uniqueIndex:idx_bonus_spin_grants_user_action_reference"`
+	ReferenceID uuid.UUID `json:"reference_id" gorm:"type:uuid;not null;uniqueIndex:idx_bonus_spin_grants_user_action_reference"`
+	Spins       int       `json:"spins" gorm:"not null"`
+	SpinsUsed   int       `json:"spins_used" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for BonusSpinGrant model
+func (BonusSpinGrant) TableName() string {
+	return "bonus_spin_grants"
+}
+
+// NewsRead marks that a user has read a news article
+type NewsRead struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_news_reads_user_news"`
+	NewsID uuid.UUID `json:"news_id" gorm:"type:uuid;not null;uniqueIndex:idx_news_reads_user_news"`
+	ReadAt time.Time `json:"read_at"`
+}
+
+// TableName specifies the table name for NewsRead model
+func (NewsRead) TableName() string {
+	return "news_reads"
+}
+
+// Bonus spin rule request models
+type UpdateBonusSpinRuleRequest struct {
+	SpinsGranted int  `json:"spins_granted"`
+	DailyCap     *int `json:"daily_cap"`
+	TotalCap     *int `json:"total_cap"`
+	IsActive     bool `json:"is_active"`
+}
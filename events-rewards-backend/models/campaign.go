@@ -0,0 +1,128 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Campaign is a time-boxed lucky draw with its own reward pool, weights,
+// spin limits and eligibility rules, run alongside the base catalog
+type Campaign struct {
+	ID                   uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name                 string         `json:"name" gorm:"not null"`
+	Description          *string        `json:"description"`
+	BannerImage          *string        `json:"banner_image"`
+	StartsAt             time.Time      `json:"starts_at" gorm:"not null;index"`
+	EndsAt               time.Time      `json:"ends_at" gorm:"not null;index"`
+	IsActive             bool           `json:"is_active" gorm:"default:true"`
+	DailySpinLimit       *int           `json:"daily_spin_limit"`
+	TotalSpinLimit       *int           `json:"total_spin_limit"`
+	RequiresVerification bool           `json:"requires_verification" gorm:"default:false"`
+	MinAccountAgeDays    int            `json:"min_account_age_days" gorm:"default:0"`
+	EligibleTiers        *string        `json:"eligible_tiers"`
+	CreatedBy            *uuid.UUID     `json:"created_by" gorm:"type:uuid"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relationships
+	Rewards []CampaignReward `json:"rewards,omitempty" gorm:"foreignKey:CampaignID"`
+}
+
+// TableName specifies the table name for Campaign model
+func (Campaign) TableName() string {
+	return "campaigns"
+}
+
+// IsRunning reports whether the campaign is active and now falls inside its schedule
+func (c *Campaign) IsRunning(now time.Time) bool {
+	return c.IsActive && !now.Before(c.StartsAt) && now.Before(c.EndsAt)
+}
+
+// TierList returns the comma-separated EligibleTiers as a slice; empty means every tier
+func (c *Campaign) TierList() []string {
+	if c.EligibleTiers == nil {
+		return nil
+	}
+
+	var tiers []string
+	for _, tier := range strings.Split(*c.EligibleTiers, ",") {
+		if tier = strings.TrimSpace(tier); tier != "" {
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+// CampaignReward puts a catalog reward into a campaign's pool with a campaign-specific weight
+type CampaignReward struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CampaignID  uuid.UUID `json:"campaign_id" gorm:"type:uuid;not null;uniqueIndex:idx_campaign_rewards_campaign_reward"`
+	RewardID    uuid.UUID `json:"reward_id" gorm:"type:uuid;not null;uniqueIndex:idx_campaign_rewards_campaign_reward"`
+	Probability float64   `json:"probability" gorm:"type:decimal(5,4);not null"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	Reward Reward `json:"reward,omitempty" gorm:"foreignKey:RewardID"`
+}
+
+// TableName specifies the table name for CampaignReward model
+func (CampaignReward) TableName() string {
+	return "campaign_rewards"
+}
+
+// CampaignSpinAttempt counts a user's spins in a campaign per day, separately
+// from their everyday spin allowance in spin_attempts
+type CampaignSpinAttempt struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_campaign_spin_attempts_user_campaign_date"`
+	CampaignID    uuid.UUID `json:"campaign_id" gorm:"type:uuid;not null;uniqueIndex:idx_campaign_spin_attempts_user_campaign_date"`
+	AttemptDate   time.Time `json:"attempt_date" gorm:"type:date;not null;uniqueIndex:idx_campaign_spin_attempts_user_campaign_date"`
+	AttemptsCount int       `json:"attempts_count" gorm:"default:1"`
+	LastAttempt   time.Time `json:"last_attempt" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for CampaignSpinAttempt model
+func (CampaignSpinAttempt) TableName() string {
+	return "campaign_spin_attempts"
+}
+
+// Campaign request models
+type CreateCampaignRequest struct {
+	Name                 string  `json:"name" validate:"required"`
+	Description          *string `json:"description"`
+	BannerImage          *string `json:"banner_image"`
+	StartsAt             string  `json:"starts_at" validate:"required"`
+	EndsAt               string  `json:"ends_at" validate:"required"`
+	DailySpinLimit       *int    `json:"daily_spin_limit"`
+	TotalSpinLimit       *int    `json:"total_spin_limit"`
+	RequiresVerification bool    `json:"requires_verification"`
+	MinAccountAgeDays    int     `json:"min_account_age_days"`
+	EligibleTiers        *string `json:"eligible_tiers"`
+}
+
+type UpdateCampaignRequest struct {
+	Name                 *string `json:"name"`
+	Description          *string `json:"description"`
+	BannerImage          *string `json:"banner_image"`
+	StartsAt             *string `json:"starts_at"`
+	EndsAt               *string `json:"ends_at"`
+	IsActive             *bool   `json:"is_active"`
+	DailySpinLimit       *int    `json:"daily_spin_limit"`
+	TotalSpinLimit       *int    `json:"total_spin_limit"`
+	RequiresVerification *bool   `json:"requires_verification"`
+	MinAccountAgeDays    *int    `json:"min_account_age_days"`
+	EligibleTiers        *string `json:"eligible_tiers"`
+}
+
+type CampaignRewardInput struct {
+	RewardID    string  `json:"reward_id" validate:"required"`
+	Probability float64 `json:"probability" validate:"required"`
+}
+
+type SetCampaignRewardsRequest struct {
+	Rewards []CampaignRewardInput `json:"rewards" validate:"required"`
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Catalog item types
+const (
+	CatalogItemVoucher      = "voucher"
+	CatalogItemMerchandise  = "merchandise"
+	CatalogItemExtraSpin    = "extra_spin"
+	CatalogItemPrioritySeat = "priority_seat"
+)
+
+// IsValidCatalogItemType reports whether itemType is one of the known catalog item types
+func IsValidCatalogItemType(itemType string) bool {
+	switch itemType {
+	case CatalogItemVoucher, CatalogItemMerchandise, CatalogItemExtraSpin, CatalogItemPrioritySeat:
+		return true
+	}
+	return false
+}
+
+// Catalog order statuses
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusFulfilled = "fulfilled"
+	OrderStatusCancelled = "cancelled"
+)
+
+// CatalogItem is something users can buy with points
+type CatalogItem struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name         string         `json:"name" gorm:"not null"`
+	Description  *string        `json:"description"`
+	ImageURL     *string        `json:"image_url"`
+	ItemType     string         `json:"item_type" gorm:"type:varchar(20);not null"`
+	PointsCost   int64          `json:"points_cost" gorm:"not null"`
+	Stock        *int           `json:"stock"`
+	TotalOrdered int            `json:"total_ordered" gorm:"not null;default:0"`
+	EventID      *uuid.UUID     `json:"event_id" gorm:"type:uuid"`
+	IsActive     bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName specifies the table name for CatalogItem model
+func (CatalogItem) TableName() string {
+	return "catalog_items"
+}
+
+// Remaining returns how many units are left, or nil when stock is unlimited
+func (c *CatalogItem) Remaining() *int {
+	if c.Stock == nil {
+		return nil
+	}
+	remaining := *c.Stock - c.TotalOrdered
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// CatalogOrder is a user's purchase of a catalog item with points
+type CatalogOrder struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	ItemID      uuid.UUID  `json:"item_id" gorm:"type:uuid;not null;index"`
+	Quantity    int        `json:"quantity" gorm:"not null;default:1"`
+	PointsSpent int64      `json:"points_spent" gorm:"not null"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:pending;index"`
+	Notes       *string    `json:"notes"`
+	FulfilledAt *time.Time `json:"fulfilled_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	Item CatalogItem `json:"item,omitempty" gorm:"foreignKey:ItemID"`
+}
+
+// TableName specifies the table name for CatalogOrder model
+func (CatalogOrder) TableName() string {
+	return "catalog_orders"
+}
+
+// Catalog request models
+type CreateCatalogItemRequest struct {
+	Name        string  `json:"name" validate:"required"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url"`
+	ItemType    string  `json:"item_type" validate:"required"`
+	PointsCost  int64   `json:"points_cost" validate:"required"`
+	Stock       *int    `json:"stock"`
+	EventID     *string `json:"event_id"`
+}
+
+type UpdateCatalogItemRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url"`
+	PointsCost  *int64  `json:"points_cost"`
+	Stock       *int    `json:"stock"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+type PlaceOrderRequest struct {
+	ItemID   string `json:"item_id" validate:"required"`
+	Quantity int    `json:"quantity"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string  `json:"status" validate:"required"`
+	Notes  *string `json:"notes"`
+}
@@ -14,8 +14,16 @@ type Event struct {
 	Description         *string        `json:"description"`
 	EventDate           time.Time      `json:"event_date" gorm:"not null"`
 	Location            *string        `json:"location"`
+	Venue               *string        `json:"venue"`
+	Latitude            *float64       `json:"latitude" gorm:"index:idx_events_coordinates"`
+	Longitude           *float64       `json:"longitude" gorm:"index:idx_events_coordinates"`
 	MaxParticipants     *int           `json:"max_participants"`
 	CurrentParticipants int            `json:"current_participants" gorm:"default:0"`
+	CheckedInCount      int            `json:"checked_in_count" gorm:"default:0"`
+	Sequence            int            `json:"sequence" gorm:"not null;default:0"`
+	WaitlistClaimHours  *int           `json:"waitlist_claim_hours"`
+	SeriesID            *uuid.UUID     `json:"series_id" gorm:"type:uuid;index"`
+	OccurrenceStart     *time.Time     `json:"occurrence_start"`
 	BannerImage         *string        `json:"banner_image"`
 	Category            *string        `json:"category"`
 	IsActive            bool           `json:"is_active" gorm:"default:true"`
@@ -24,9 +32,13 @@ type Event struct {
 	UpdatedAt           time.Time      `json:"updated_at"`
 	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// DistanceKm is filled in by location searches and is not stored
+	DistanceKm *float64 `json:"distance_km,omitempty" gorm:"-"`
+
 	// Relationships
 	Creator       *User               `json:"creator,omitempty" gorm:"foreignKey:CreatedBy"`
 	Registrations []EventRegistration `json:"registrations,omitempty" gorm:"foreignKey:EventID"`
+	TicketTiers   []TicketTier        `json:"ticket_tiers,omitempty" gorm:"foreignKey:EventID"`
 }
 
 // TableName specifies the table name for Event model
@@ -34,17 +46,33 @@ func (Event) TableName() string {
 	return "events"
 }
 
+// Event registration statuses. Registered, offered and checked-in registrations
+// hold a seat; waitlisted ones queue for the next free seat in registration order.
+const (
+	RegistrationStatusRegistered = "registered"
+	RegistrationStatusWaitlisted = "waitlisted"
+	RegistrationStatusOffered    = "offered"
+	RegistrationStatusCheckedIn  = "checked_in"
+)
+
 // EventRegistration model
 type EventRegistration struct {
-	ID               uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID           uuid.UUID `json:"user_id" gorm:"not null"`
-	EventID          uuid.UUID `json:"event_id" gorm:"not null"`
-	RegistrationDate time.Time `json:"registration_date" gorm:"default:CURRENT_TIMESTAMP"`
-	Status           string    `json:"status" gorm:"default:registered"`
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"not null"`
+	EventID          uuid.UUID  `json:"event_id" gorm:"not null"`
+	RegistrationDate time.Time  `json:"registration_date" gorm:"default:CURRENT_TIMESTAMP"`
+	Status           string     `json:"status" gorm:"default:registered"`
+	TicketTierID     *uuid.UUID `json:"ticket_tier_id" gorm:"type:uuid;index"`
+	Quantity         int        `json:"quantity" gorm:"not null;default:1"`
+	PromotedAt       *time.Time `json:"promoted_at"`
+	ClaimExpiresAt   *time.Time `json:"claim_expires_at"`
+	CheckedInAt      *time.Time `json:"checked_in_at"`
+	CheckedInBy      *uuid.UUID `json:"checked_in_by" gorm:"type:uuid"`
 
 	// Relationships
-	User  User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID"`
+	User       User        `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Event      Event       `json:"event,omitempty" gorm:"foreignKey:EventID"`
+	TicketTier *TicketTier `json:"ticket_tier,omitempty" gorm:"foreignKey:TicketTierID"`
 }
 
 // TableName specifies the table name for EventRegistration model
@@ -63,22 +91,35 @@ type EventFilter struct {
 }
 
 type CreateEventRequest struct {
-	Title           string  `json:"title" validate:"required"`
-	Description     string  `json:"description"`
-	EventDateStr    string  `json:"eventdate" validate:"required"`
-	Location        string  `json:"location"`
-	MaxParticipants *int    `json:"maxparticipants"`
-	BannerImage     *string `json:"bannerimage"`
-	Category        string  `json:"category"`
+	Title              string   `json:"title" validate:"required"`
+	Description        string   `json:"description"`
+	EventDateStr       string   `json:"eventdate" validate:"required"`
+	Location           string   `json:"location"`
+	Venue              *string  `json:"venue"`
+	Latitude           *float64 `json:"latitude"`
+	Longitude          *float64 `json:"longitude"`
+	MaxParticipants    *int     `json:"maxparticipants"`
+	BannerImage        *string  `json:"bannerimage"`
+	Category           string   `json:"category"`
+	WaitlistClaimHours *int     `json:"waitlistclaimhours"`
 }
 
 type UpdateEventRequest struct {
-	Title           *string    `json:"title"`
-	Description     *string    `json:"description"`
-	EventDate       *time.Time `json:"event_date"`
-	Location        *string    `json:"location"`
-	MaxParticipants *int       `json:"max_participants"`
-	BannerImage     *string    `json:"banner_image"`
-	Category        *string    `json:"category"`
-	IsActive        *bool      `json:"is_active"`
+	Title              *string    `json:"title"`
+	Description        *string    `json:"description"`
+	EventDate          *time.Time `json:"event_date"`
+	Location           *string    `json:"location"`
+	Venue              *string    `json:"venue"`
+	Latitude           *float64   `json:"latitude"`
+	Longitude          *float64   `json:"longitude"`
+	MaxParticipants    *int       `json:"max_participants"`
+	BannerImage        *string    `json:"banner_image"`
+	Category           *string    `json:"category"`
+	IsActive           *bool      `json:"is_active"`
+	WaitlistClaimHours *int       `json:"waitlist_claim_hours"`
+}
+
+// Check-in request models
+type CheckInRequest struct {
+	Token string `json:"token" validate:"required"`
 }
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Recurrence frequencies for event series
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// Edit scopes for events that belong to a series
+const (
+	SeriesScopeThis   = "this"
+	SeriesScopeFuture = "future"
+)
+
+// StringList is a list of strings stored as JSONB
+type StringList []string
+
+func (d StringList) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+func (d *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// Contains reports whether date is in the list
+func (d StringList) Contains(date string) bool {
+	for _, existing := range d {
+		if existing == date {
+			return true
+		}
+	}
+	return false
+}
+
+// EventSeries is a recurring event. Its recurrence follows a subset of RRULE
+// (FREQ, INTERVAL, BYDAY for weekly series, UNTIL and COUNT, plus excluded
+// dates in YYYY-MM-DD form), and each occurrence is materialized as an Event
+// linked by SeriesID.
+type EventSeries struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Title           string         `json:"title" gorm:"not null"`
+	Description     *string        `json:"description"`
+	Location        *string        `json:"location"`
+	MaxParticipants *int           `json:"max_participants"`
+	BannerImage     *string        `json:"banner_image"`
+	Category        *string        `json:"category"`
+	StartsAt        time.Time      `json:"starts_at" gorm:"not null"`
+	Timezone        string         `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	Frequency       string         `json:"frequency" gorm:"type:varchar(10);not null"`
+	Interval        int            `json:"interval" gorm:"not null;default:1"`
+	ByWeekday       StringList     `json:"by_weekday" gorm:"type:jsonb"`
+	Until           *time.Time     `json:"until"`
+	Count           *int           `json:"count"`
+	Exceptions      StringList     `json:"exceptions" gorm:"type:jsonb"`
+	CreatedBy       *uuid.UUID     `json:"created_by"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relationships
+	Occurrences []Event `json:"occurrences,omitempty" gorm:"foreignKey:SeriesID"`
+}
+
+// TableName specifies the table name for EventSeries model
+func (EventSeries) TableName() string {
+	return "event_series"
+}
+
+// Event series request models
+type CreateEventSeriesRequest struct {
+	Title           string     `json:"title" validate:"required"`
+	Description     *string    `json:"description"`
+	Location        *string    `json:"location"`
+	MaxParticipants *int       `json:"max_participants"`
+	BannerImage     *string    `json:"banner_image"`
+	Category        *string    `json:"category"`
+	StartsAt        time.Time  `json:"starts_at" validate:"required"`
+	Timezone        string     `json:"timezone"`
+	Frequency       string     `json:"frequency" validate:"required"`
+	Interval        int        `json:"interval"`
+	ByWeekday       []string   `json:"by_weekday"`
+	Until           *time.Time `json:"until"`
+	Count           *int       `json:"count"`
+	Exceptions      []string   `json:"exceptions"`
+}
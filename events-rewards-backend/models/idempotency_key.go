@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey stores the first response to a request sent with an
+// Idempotency-Key header so retries of the same request can be replayed.
+// Keys are scoped to the caller: the user ID, or "anonymous" on public routes.
+type IdempotencyKey struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Scope       string    `json:"scope" gorm:"type:varchar(64);not null;uniqueIndex:idx_idempotency_keys_scope_key"`
+	Key         string    `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_scope_key"`
+	Fingerprint string    `json:"fingerprint" gorm:"type:varchar(64);not null"`
+	Completed   bool      `json:"completed" gorm:"not null;default:false"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(100)"`
+	Body        []byte    `json:"-" gorm:"type:bytea"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for IdempotencyKey model
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Points account kinds. Every user has one user account; the system accounts
+// are the other side of every credit and debit so the ledger always balances.
+const (
+	PointsAccountUser        = "user"
+	PointsAccountRewardsPool = "rewards_pool"
+	PointsAccountRedemptions = "redemptions"
+)
+
+// Points transaction kinds
+const (
+	PointsTxnRewardCredit     = "reward_credit"
+	PointsTxnRedemptionDebit  = "redemption_debit"
+	PointsTxnRedemptionRefund = "redemption_refund"
+)
+
+// PointsAccount holds a points balance. For user accounts Balance is a running
+// total kept in step with the account's ledger entries and checked by
+// reconciliation. System accounts have no UserID, exist once per kind and
+// store no balance; theirs is the sum of their entries.
+type PointsAccount struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Kind      string     `json:"kind" gorm:"type:varchar(20);not null;uniqueIndex:idx_points_accounts_kind_user;index:idx_points_accounts_system_kind,unique,where:user_id IS NULL"`
+	UserID    *uuid.UUID `json:"user_id" gorm:"type:uuid;uniqueIndex:idx_points_accounts_kind_user"`
+	Balance   int64      `json:"balance" gorm:"not null;default:0"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for PointsAccount model
+func (PointsAccount) TableName() string {
+	return "points_accounts"
+}
+
+// PointsTransaction groups ledger entries that move points between accounts.
+// Its entries always sum to zero. Kind and ReferenceID together are unique, so
+// the same claim or order can never be posted twice.
+type PointsTransaction struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Kind          string    `json:"kind" gorm:"type:varchar(30);not null;uniqueIndex:idx_points_transactions_kind_reference"`
+	ReferenceType string    `json:"reference_type" gorm:"type:varchar(30);not null"`
+	ReferenceID   uuid.UUID `json:"reference_id" gorm:"type:uuid;not null;uniqueIndex:idx_points_transactions_kind_reference"`
+	Description   string    `json:"description"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relationships
+	Entries []PointsEntry `json:"entries,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// TableName specifies the table name for PointsTransaction model
+func (PointsTransaction) TableName() string {
+	return "points_transactions"
+}
+
+// PointsEntry is one side of a points transaction: a positive amount credits
+// the account and a negative amount debits it. BalanceAfter is the user's
+// balance after the entry and is 0 on system account entries.
+type PointsEntry struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TransactionID uuid.UUID `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	AccountID     uuid.UUID `json:"account_id" gorm:"type:uuid;not null;index"`
+	Amount        int64     `json:"amount" gorm:"not null"`
+	BalanceAfter  int64     `json:"balance_after" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relationships
+	Transaction PointsTransaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// TableName specifies the table name for PointsEntry model
+func (PointsEntry) TableName() string {
+	return "points_entries"
+}
@@ -18,6 +18,8 @@ type Reward struct {
 	TotalAvailable *int           `json:"total_available"`
 	TotalClaimed   int            `json:"total_claimed" gorm:"default:0"`
 	IsActive       bool           `json:"is_active" gorm:"default:true"`
+	IsConsolation  bool           `json:"is_consolation" gorm:"default:false"`
+	CampaignOnly   bool           `json:"campaign_only" gorm:"not null;default:false"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
@@ -31,17 +33,69 @@ func (Reward) TableName() string {
 	return "rewards"
 }
 
+// InBasePool reports whether the reward is drawn by everyday spins. Campaign-only
+// rewards are drawn solely through the campaigns that list them.
+func (r *Reward) InBasePool() bool {
+	return r.IsActive && !r.CampaignOnly
+}
+
+// RewardBasePoolCondition is the SQL form of InBasePool
+const RewardBasePoolCondition = "is_active = true AND campaign_only = false"
+
+// RewardInStockCondition is the SQL condition for a reward that can still be
+// won. A NULL total_available means unlimited stock.
+const RewardInStockCondition = "(total_available IS NULL OR total_claimed < total_available)"
+
+// Reward types
+const (
+	RewardTypeNone     = "none"
+	RewardTypePoints   = "points"
+	RewardTypeDiscount = "discount"
+	RewardTypeProduct  = "product"
+)
+
+// Probability bounds enforced by the rewards_probability_check constraint
+const (
+	MinRewardProbability = 0.0001
+	MaxRewardProbability = 1.0
+)
+
+// RewardWeightChange is an audit record of a change to a reward's draw weight
+// or active status, both of which change the odds of every other reward
+type RewardWeightChange struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	RewardID       uuid.UUID `json:"reward_id" gorm:"type:uuid;not null;index"`
+	ChangedBy      uuid.UUID `json:"changed_by" gorm:"type:uuid;not null"`
+	ChangeType     string    `json:"change_type" gorm:"type:varchar(20);not null"`
+	OldProbability *float64  `json:"old_probability" gorm:"type:decimal(5,4)"`
+	NewProbability float64   `json:"new_probability" gorm:"type:decimal(5,4);not null"`
+	OldIsActive    *bool     `json:"old_is_active"`
+	NewIsActive    bool      `json:"new_is_active"`
+	Reason         *string   `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for RewardWeightChange model
+func (RewardWeightChange) TableName() string {
+	return "reward_weight_history"
+}
+
 // UserReward model
 type UserReward struct {
-	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID    uuid.UUID  `json:"user_id" gorm:"not null"`
-	RewardID  uuid.UUID  `json:"reward_id" gorm:"not null"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	ClaimedAt *time.Time `json:"claimed_at"`
-	Status    string     `json:"status" gorm:"default:pending"`
-	ClaimCode *string    `json:"claim_code"`
-	ExpiresAt *time.Time `json:"expires_at"`
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"not null"`
+	RewardID   uuid.UUID  `json:"reward_id" gorm:"not null"`
+	CampaignID *uuid.UUID `json:"campaign_id" gorm:"type:uuid"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ClaimedAt  *time.Time `json:"claimed_at"`
+	Status     string     `json:"status" gorm:"default:pending"`
+	ClaimCode  *string    `json:"claim_code"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+
+	// Set when staff redeem the reward at a counter
+	RedeemedBy         *uuid.UUID `json:"redeemed_by" gorm:"type:uuid"`
+	RedemptionLocation *string    `json:"redemption_location"`
 
 	// Relationships
 	User   User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
@@ -56,8 +110,8 @@ func (UserReward) TableName() string {
 // SpinAttempt model
 type SpinAttempt struct {
 	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	UserID        uuid.UUID `json:"user_id" gorm:"not null"`
-	AttemptDate   time.Time `json:"attempt_date" gorm:"type:date;not null"`
+	UserID        uuid.UUID `json:"user_id" gorm:"not null;uniqueIndex:spin_attempts_user_id_attempt_date_key"`
+	AttemptDate   time.Time `json:"attempt_date" gorm:"type:date;not null;uniqueIndex:spin_attempts_user_id_attempt_date_key"`
 	AttemptsCount int       `json:"attempts_count" gorm:"default:1"`
 	LastAttempt   time.Time `json:"last_attempt" gorm:"default:CURRENT_TIMESTAMP"`
 
@@ -70,9 +124,44 @@ func (SpinAttempt) TableName() string {
 	return "spin_attempts"
 }
 
+// Spin outcomes recorded on SpinEvent
+const (
+	SpinOutcomeWon         = "won"
+	SpinOutcomeConsolation = "consolation"
+	SpinOutcomeNoWin       = "no_win"
+)
+
+// SpinEvent is the append-only audit record of a single spin: who spun, in
+// which campaign, the random value drawn, what was chosen and awarded, and the
+// device and IP it came from. A database trigger rejects updates and deletes.
+type SpinEvent struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index:idx_spin_events_user_created"`
+	CampaignID      *uuid.UUID `json:"campaign_id" gorm:"type:uuid;index"`
+	SpinProofID     *uuid.UUID `json:"spin_proof_id" gorm:"type:uuid"`
+	RandomValue     float64    `json:"random_value" gorm:"type:double precision;not null;default:0"`
+	RewardID        uuid.UUID  `json:"reward_id" gorm:"type:uuid;not null"`
+	AwardedRewardID *uuid.UUID `json:"awarded_reward_id" gorm:"type:uuid"`
+	UserRewardID    *uuid.UUID `json:"user_reward_id" gorm:"type:uuid"`
+	Outcome         string     `json:"outcome" gorm:"type:varchar(20);not null"`
+	DeviceID        *string    `json:"device_id" gorm:"type:varchar(255)"`
+	IPAddress       *string    `json:"ip_address" gorm:"type:varchar(45)"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"index:idx_spin_events_user_created;index"`
+
+	// Relationships
+	Reward        Reward  `json:"reward,omitempty" gorm:"foreignKey:RewardID"`
+	AwardedReward *Reward `json:"awarded_reward,omitempty" gorm:"foreignKey:AwardedRewardID"`
+}
+
+// TableName specifies the table name for SpinEvent model
+func (SpinEvent) TableName() string {
+	return "spin_events"
+}
+
 // Lucky Draw request/response models
 type SpinRequest struct {
-	UserID string `json:"user_id"`
+	UserID     string `json:"user_id"`
+	CampaignID string `json:"campaign_id"`
 }
 
 type SpinResponse struct {
@@ -83,10 +172,42 @@ type SpinResponse struct {
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
+// Reward catalog management request models
+type CreateRewardRequest struct {
+	Name           string   `json:"name" validate:"required"`
+	Description    string   `json:"description"`
+	RewardType     string   `json:"reward_type" validate:"required"`
+	Value          *float64 `json:"value"`
+	Probability    float64  `json:"probability" validate:"required"`
+	TotalAvailable *int     `json:"total_available"`
+	IsActive       *bool    `json:"is_active"`
+	IsConsolation  bool     `json:"is_consolation"`
+	CampaignOnly   bool     `json:"campaign_only"`
+	Reason         string   `json:"reason"`
+}
+
+type UpdateRewardRequest struct {
+	Name           *string  `json:"name"`
+	Description    *string  `json:"description"`
+	RewardType     *string  `json:"reward_type"`
+	Value          *float64 `json:"value"`
+	Probability    *float64 `json:"probability"`
+	TotalAvailable *int     `json:"total_available"`
+	IsActive       *bool    `json:"is_active"`
+	IsConsolation  *bool    `json:"is_consolation"`
+	CampaignOnly   *bool    `json:"campaign_only"`
+	Reason         string   `json:"reason"`
+}
+
 type RewardClaimRequest struct {
 	ClaimCode string `json:"claim_code" validate:"required"`
 }
 
+type RedeemRewardRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Location string `json:"location" validate:"required"`
+}
+
 // Reward statistics
 type RewardStats struct {
 	TotalRewards   int     `json:"total_rewards"`
@@ -100,4 +221,5 @@ type UserRewardStats struct {
 	TotalWins      int `json:"total_wins"`
 	PendingRewards int `json:"pending_rewards"`
 	ClaimedRewards int `json:"claimed_rewards"`
+	ExpiredRewards int `json:"expired_rewards"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Search result types
+const (
+	SearchTypeEvent = "event"
+	SearchTypeNews  = "news"
+)
+
+// SearchResult is one ranked match from the full-text search. Title and
+// Snippet mark matched terms with <mark> tags.
+type SearchResult struct {
+	Type     string     `json:"type"`
+	ID       uuid.UUID  `json:"id"`
+	Title    string     `json:"title"`
+	Snippet  string     `json:"snippet"`
+	Category *string    `json:"category"`
+	Date     *time.Time `json:"date"`
+	Rank     float64    `json:"rank"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Built-in spin policy values, used when no policy has been configured
+const (
+	DefaultDailySpinLimit  = 3
+	DefaultResetTimezone   = "Asia/Kolkata"
+	DefaultClaimExpiryDays = 7
+)
+
+// SpinPolicy controls how often users may spin and how long won rewards stay
+// claimable. The policy with a nil Tier is the default; a policy with a Tier
+// overrides it for users in that tier.
+type SpinPolicy struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name            string     `json:"name" gorm:"not null"`
+	Tier            *string    `json:"tier" gorm:"type:varchar(50);uniqueIndex"`
+	DailyLimit      int        `json:"daily_limit" gorm:"not null;default:3"`
+	WeeklyLimit     *int       `json:"weekly_limit"`
+	ResetTimezone   string     `json:"reset_timezone" gorm:"type:varchar(64);not null;default:Asia/Kolkata"`
+	CooldownSeconds int        `json:"cooldown_seconds" gorm:"not null;default:0"`
+	ClaimExpiryDays int        `json:"claim_expiry_days" gorm:"not null;default:7"`
+	IsActive        bool       `json:"is_active" gorm:"default:true"`
+	UpdatedBy       *uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for SpinPolicy model
+func (SpinPolicy) TableName() string {
+	return "spin_policies"
+}
+
+// DefaultSpinPolicy returns the built-in policy used until an admin configures one
+func DefaultSpinPolicy() SpinPolicy {
+	return SpinPolicy{
+		Name:            "Built-in default",
+		DailyLimit:      DefaultDailySpinLimit,
+		ResetTimezone:   DefaultResetTimezone,
+		ClaimExpiryDays: DefaultClaimExpiryDays,
+		IsActive:        true,
+	}
+}
+
+// Location returns the policy's reset timezone, falling back to UTC if it cannot be loaded
+func (p *SpinPolicy) Location() *time.Location {
+	location, err := time.LoadLocation(p.ResetTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// SpinDay returns the calendar day that now falls on in the policy's timezone,
+// as a UTC midnight suitable for the spin_attempts.attempt_date column
+func (p *SpinPolicy) SpinDay(now time.Time) time.Time {
+	local := now.In(p.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SpinWeekStart returns the Monday of the week containing now, in the same form as SpinDay
+func (p *SpinPolicy) SpinWeekStart(now time.Time) time.Time {
+	day := p.SpinDay(now)
+	offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// Cooldown returns the minimum time between two spins
+func (p *SpinPolicy) Cooldown() time.Duration {
+	return time.Duration(p.CooldownSeconds) * time.Second
+}
+
+// Spin policy request models
+type CreateSpinPolicyRequest struct {
+	Name            string  `json:"name" validate:"required"`
+	Tier            *string `json:"tier"`
+	DailyLimit      int     `json:"daily_limit"`
+	WeeklyLimit     *int    `json:"weekly_limit"`
+	ResetTimezone   string  `json:"reset_timezone"`
+	CooldownSeconds int     `json:"cooldown_seconds"`
+	ClaimExpiryDays int     `json:"claim_expiry_days"`
+}
+
+type UpdateSpinPolicyRequest struct {
+	Name             *string `json:"name"`
+	DailyLimit       *int    `json:"daily_limit"`
+	WeeklyLimit      *int    `json:"weekly_limit"`
+	ClearWeeklyLimit bool    `json:"clear_weekly_limit"`
+	ResetTimezone    *string `json:"reset_timezone"`
+	CooldownSeconds  *int    `json:"cooldown_seconds"`
+	ClaimExpiryDays  *int    `json:"claim_expiry_days"`
+	IsActive         *bool   `json:"is_active"`
+}
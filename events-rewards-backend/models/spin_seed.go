@@ -0,0 +1,95 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpinSeed is the server seed for one draw period. Its SHA-256 hash is
+// published while the period runs and the seed itself is revealed once the
+// period is over, so every spin in the period can be reproduced.
+type SpinSeed struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	PeriodStart time.Time  `json:"period_start" gorm:"not null;uniqueIndex"`
+	PeriodEnd   time.Time  `json:"period_end" gorm:"not null;index"`
+	Seed        string     `json:"-" gorm:"type:varchar(64);not null"`
+	SeedHash    string     `json:"seed_hash" gorm:"type:varchar(64);not null"`
+	RevealedAt  *time.Time `json:"revealed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for SpinSeed model
+func (SpinSeed) TableName() string {
+	return "spin_seeds"
+}
+
+// IsRevealed reports whether the seed may be published
+func (s *SpinSeed) IsRevealed() bool {
+	return s.RevealedAt != nil
+}
+
+// SpinPoolEntry is one reward and its weight as it stood when a spin was drawn
+type SpinPoolEntry struct {
+	RewardID    uuid.UUID `json:"reward_id"`
+	Name        string    `json:"name"`
+	Probability float64   `json:"probability"`
+}
+
+// SpinPool is the ordered reward pool a spin was drawn from, stored as JSONB
+type SpinPool []SpinPoolEntry
+
+func (p SpinPool) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *SpinPool) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, p)
+}
+
+// SpinProof records everything needed to reproduce a spin: the seed period,
+// the user's nonce within it, the derived random value and the pool it was
+// drawn from. Nonces count up from 1 per user per seed.
+type SpinProof struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_spin_proofs_seed_user_nonce"`
+	SeedID      uuid.UUID  `json:"seed_id" gorm:"type:uuid;not null;uniqueIndex:idx_spin_proofs_seed_user_nonce"`
+	Nonce       int64      `json:"nonce" gorm:"not null;uniqueIndex:idx_spin_proofs_seed_user_nonce"`
+	RandomValue float64    `json:"random_value" gorm:"type:double precision;not null"`
+	Pool        SpinPool   `json:"pool" gorm:"type:jsonb;not null"`
+	RewardID    uuid.UUID  `json:"reward_id" gorm:"type:uuid;not null"`
+	CampaignID  *uuid.UUID `json:"campaign_id" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Relationships
+	Seed SpinSeed `json:"-" gorm:"foreignKey:SeedID"`
+}
+
+// TableName specifies the table name for SpinProof model
+func (SpinProof) TableName() string {
+	return "spin_proofs"
+}
+
+// Fairness request models
+type VerifySpinRequest struct {
+	SpinID   string   `json:"spin_id"`
+	Seed     string   `json:"seed"`
+	SeedHash string   `json:"seed_hash"`
+	UserID   string   `json:"user_id"`
+	Nonce    int64    `json:"nonce"`
+	Pool     SpinPool `json:"pool"`
+	RewardID string   `json:"reward_id"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TicketTier is one kind of ticket for an event, such as general admission,
+// VIP or student, with its own capacity, price, sale window and per-user limit
+type TicketTier struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	EventID      uuid.UUID      `json:"event_id" gorm:"type:uuid;not null;index"`
+	Name         string         `json:"name" gorm:"type:varchar(100);not null"`
+	Description  *string        `json:"description"`
+	Price        float64        `json:"price" gorm:"type:decimal(10,2);not null;default:0"`
+	Capacity     *int           `json:"capacity"`
+	Sold         int            `json:"sold" gorm:"not null;default:0"`
+	PerUserLimit int            `json:"per_user_limit" gorm:"not null;default:1"`
+	SalesStartAt *time.Time     `json:"sales_start_at"`
+	SalesEndAt   *time.Time     `json:"sales_end_at"`
+	SortOrder    int            `json:"sort_order" gorm:"not null;default:0"`
+	IsActive     bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName specifies the table name for TicketTier model
+func (TicketTier) TableName() string {
+	return "ticket_tiers"
+}
+
+// Remaining returns how many tickets are left, or nil when capacity is unlimited
+func (t *TicketTier) Remaining() *int {
+	if t.Capacity == nil {
+		return nil
+	}
+	remaining := *t.Capacity - t.Sold
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// OnSale reports whether the tier is active and inside its sale window at now
+func (t *TicketTier) OnSale(now time.Time) bool {
+	if !t.IsActive {
+		return false
+	}
+	if t.SalesStartAt != nil && now.Before(*t.SalesStartAt) {
+		return false
+	}
+	if t.SalesEndAt != nil && !now.Before(*t.SalesEndAt) {
+		return false
+	}
+	return true
+}
+
+// Ticket tier request models
+type CreateTicketTierRequest struct {
+	Name         string     `json:"name" validate:"required"`
+	Description  *string    `json:"description"`
+	Price        float64    `json:"price"`
+	Capacity     *int       `json:"capacity"`
+	PerUserLimit *int       `json:"per_user_limit"`
+	SalesStartAt *time.Time `json:"sales_start_at"`
+	SalesEndAt   *time.Time `json:"sales_end_at"`
+	SortOrder    int        `json:"sort_order"`
+}
+
+type UpdateTicketTierRequest struct {
+	Name         *string    `json:"name"`
+	Description  *string    `json:"description"`
+	Price        *float64   `json:"price"`
+	Capacity     *int       `json:"capacity"`
+	PerUserLimit *int       `json:"per_user_limit"`
+	SalesStartAt *time.Time `json:"sales_start_at"`
+	SalesEndAt   *time.Time `json:"sales_end_at"`
+	SortOrder    *int       `json:"sort_order"`
+	IsActive     *bool      `json:"is_active"`
+}
+
+type RegisterForEventRequest struct {
+	TicketTierID string `json:"ticket_tier_id"`
+	Quantity     int    `json:"quantity"`
+}
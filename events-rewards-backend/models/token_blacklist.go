@@ -6,11 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// TokenBlacklist records revoked access tokens by their JWT ID (jti)
 type TokenBlacklist struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Token     string    `json:"token" gorm:"type:text;not null;uniqueIndex"`
+	JTI       string    `json:"jti" gorm:"type:varchar(64);not null;uniqueIndex"`
 	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
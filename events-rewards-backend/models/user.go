@@ -31,6 +31,24 @@ func (j *JSONB) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, j)
 }
 
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleStaff     = "staff"
+	RoleOrganizer = "organizer"
+	RoleEditor    = "editor"
+	RoleAdmin     = "admin"
+)
+
+// IsValidRole reports whether role is one of the known user roles
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleStaff, RoleOrganizer, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	Email        string         `json:"email" gorm:"type:varchar(255);unique;not null" validate:"required,email"`
@@ -40,6 +58,8 @@ type User struct {
 	Phone        *string        `json:"phone"`
 	IsVerified   bool           `json:"is_verified" gorm:"default:false"`
 	IsActive     bool           `json:"is_active" gorm:"default:true"`
+	Role         string         `json:"role" gorm:"type:varchar(20);not null;default:user"`
+	Tier         *string        `json:"tier" gorm:"type:varchar(50)"`
 	SelfiePath   *string        `json:"selfie_path"`
 	VoicePath    *string        `json:"voice_path"`
 	DeviceID     *string        `json:"device_id"`
@@ -54,3 +74,13 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// User request models
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// UpdateUserTierRequest sets the tier used to pick a user's spin policy; a null tier clears it
+type UpdateUserTierRequest struct {
+	Tier *string `json:"tier"`
+}
@@ -2,35 +2,62 @@ package services
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/Hritikpandey-ops/events-rewards-backend/models"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Default token lifetimes, overridable through SetTokenLifetimes
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
 )
 
 type AuthService struct {
-	db        *gorm.DB
-	jwtSecret []byte
+	db              *gorm.DB
+	jwtSecret       []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Email    string `json:"email"`
 	DeviceID string `json:"device_id"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
 func NewAuthService(db *gorm.DB, jwtSecret string) *AuthService {
 	return &AuthService{
-		db:        db,
-		jwtSecret: []byte(jwtSecret),
+		db:              db,
+		jwtSecret:       []byte(jwtSecret),
+		accessTokenTTL:  DefaultAccessTokenTTL,
+		refreshTokenTTL: DefaultRefreshTokenTTL,
 	}
 }
 
+// SetTokenLifetimes overrides the access and refresh token lifetimes
+func (s *AuthService) SetTokenLifetimes(accessTTL, refreshTTL time.Duration) {
+	s.accessTokenTTL = accessTTL
+	s.refreshTokenTTL = refreshTTL
+}
+
 func (s *AuthService) HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	return string(bytes), err
@@ -42,24 +69,176 @@ func (s *AuthService) CheckPassword(password, hash string) bool {
 }
 
 func (s *AuthService) GenerateJWT(user *models.User) (string, error) {
+	token, _, err := s.generateAccessToken(user)
+	return token, err
+}
+
+func (s *AuthService) generateAccessToken(user *models.User) (string, time.Time, error) {
 	deviceID := ""
 	if user.DeviceID != nil {
 		deviceID = *user.DeviceID
 	}
 
+	now := time.Now()
+	expiresAt := now.Add(s.accessTokenTTL)
+
 	claims := &Claims{
 		UserID:   user.ID.String(),
 		Email:    user.Email,
 		DeviceID: deviceID,
+		Role:     user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	signed, err := token.SignedString(s.jwtSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+// IssueTokenPair issues an access token and starts a new refresh token family,
+// used on login and registration
+func (s *AuthService) IssueTokenPair(user *models.User) (*models.TokenPair, error) {
+	var pair *models.TokenPair
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		pair, _, err = s.issueTokenPair(tx, user, uuid.New())
+		return err
+	})
+	return pair, err
+}
+
+func (s *AuthService) issueTokenPair(tx *gorm.DB, user *models.User, familyID uuid.UUID) (*models.TokenPair, *models.RefreshToken, error) {
+	accessToken, accessExpiresAt, err := s.generateAccessToken(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawRefresh, err := generateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refresh := &models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(rawRefresh),
+		DeviceID:  user.DeviceID,
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	}
+
+	if err := tx.Create(refresh).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     rawRefresh,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, refresh, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new token pair in the same
+// family. Presenting a token that was already rotated revokes the whole family,
+// since it means the token was copied and both copies are now in use.
+func (s *AuthService) RotateRefreshToken(rawRefresh string) (*models.TokenPair, *models.User, error) {
+	var pair *models.TokenPair
+	var user models.User
+	reused := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashRefreshToken(rawRefresh)).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused = true
+			return s.revokeFamily(tx, current.FamilyID)
+		}
+
+		if time.Now().After(current.ExpiresAt) {
+			return ErrRefreshTokenExpired
+		}
+
+		if err := tx.Where("id = ? AND is_active = ?", current.UserID, true).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		var next *models.RefreshToken
+		var err error
+		pair, next, err = s.issueTokenPair(tx, &user, current.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":  now,
+			"replaced_by": next.ID,
+		}).Error
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+	if reused {
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	return pair, &user, nil
+}
+
+// RevokeRefreshToken revokes the family the given refresh token belongs to,
+// provided it was issued to userID
+func (s *AuthService) RevokeRefreshToken(rawRefresh string, userID uuid.UUID) error {
+	var token models.RefreshToken
+	if err := s.db.Where("token_hash = ? AND user_id = ?", hashRefreshToken(rawRefresh), userID).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+
+	return s.revokeFamily(s.db, token.FamilyID)
+}
+
+func (s *AuthService) revokeFamily(tx *gorm.DB, familyID uuid.UUID) error {
+	return tx.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// generateRefreshToken returns a random opaque token suitable for clients to store
+func generateRefreshToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashRefreshToken(rawRefresh string) string {
+	sum := sha256.Sum256([]byte(rawRefresh))
+	return hex.EncodeToString(sum[:])
 }
 
 func (s *AuthService) ValidateJWT(tokenString string) (*Claims, error) {
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BonusSpinService is the rules engine that turns actions elsewhere in the
+// app into extra lucky draw spins. It listens on the EventBus, applies the
+// configured rule and caps for the action, and records a BonusSpinGrant.
+type BonusSpinService struct {
+	db *gorm.DB
+}
+
+func NewBonusSpinService(db *gorm.DB) *BonusSpinService {
+	return &BonusSpinService{db: db}
+}
+
+// Subscribe registers the rules engine for every event that can earn spins
+func (s *BonusSpinService) Subscribe(bus *EventBus) {
+	actions := map[string]string{
+		EventEventRegistered: models.BonusActionEventRegistration,
+		EventEventCheckedIn:  models.BonusActionEventCheckIn,
+		EventUserVerified:    models.BonusActionIdentityVerification,
+		EventNewsRead:        models.BonusActionNewsRead,
+	}
+
+	for eventType, action := range actions {
+		action := action
+		bus.Subscribe(eventType, func(event Event) {
+			userID, ok := event.Payload["user_id"].(uuid.UUID)
+			referenceID, hasReference := event.Payload["reference_id"].(uuid.UUID)
+			if !ok || !hasReference {
+				log.Printf("Bonus spins: %s event is missing user_id or reference_id", event.Type)
+				return
+			}
+			if _, err := s.Grant(userID, action, referenceID, 1); err != nil {
+				log.Printf("Bonus spins: failed to grant %s to %s: %v", action, userID, err)
+			}
+		})
+	}
+}
+
+// Rule returns the configured rule for an action, falling back to the built-in default
+func (s *BonusSpinService) Rule(db *gorm.DB, action string) (models.BonusSpinRule, error) {
+	var rules []models.BonusSpinRule
+	if err := db.Where("action = ?", action).Limit(1).Find(&rules).Error; err != nil {
+		return models.BonusSpinRule{}, err
+	}
+	if len(rules) > 0 {
+		return rules[0], nil
+	}
+	return models.DefaultBonusSpinRules()[action], nil
+}
+
+// Grant applies the rule for action and records the spins earned, multiplied
+// by units. It returns the number of spins granted, which is zero when the rule
+// is inactive, a cap has been reached or the reference already paid out.
+func (s *BonusSpinService) Grant(userID uuid.UUID, action string, referenceID uuid.UUID, units int) (int, error) {
+	granted := 0
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		rule, err := s.Rule(tx, action)
+		if err != nil {
+			return err
+		}
+		if !rule.IsActive || rule.SpinsGranted <= 0 || units <= 0 {
+			return nil
+		}
+
+		// Serialize grants per user so caps cannot be overshot by concurrent events
+		if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", userID).Error; err != nil {
+			return err
+		}
+
+		if rule.TotalCap != nil {
+			var total int64
+			if err := tx.Model(&models.BonusSpinGrant{}).Where("user_id = ? AND action = ?", userID, action).Count(&total).Error; err != nil {
+				return err
+			}
+			if total >= int64(*rule.TotalCap) {
+				return nil
+			}
+		}
+		if rule.DailyCap != nil {
+			var today int64
+			if err := tx.Model(&models.BonusSpinGrant{}).
+				Where("user_id = ? AND action = ? AND created_at >= ?", userID, action, time.Now().Add(-24*time.Hour)).
+				Count(&today).Error; err != nil {
+				return err
+			}
+			if today >= int64(*rule.DailyCap) {
+				return nil
+			}
+		}
+
+		grant := models.BonusSpinGrant{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Action:      action,
+			ReferenceID: referenceID,
+			Spins:       rule.SpinsGranted * units,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grant)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			granted = grant.Spins
+		}
+		return nil
+	})
+
+	return granted, err
+}
+
+// GrantPurchased records the spins bought with a catalog order. It must run in
+// the order's transaction so the spins and the points debit stand or fall
+// together. Bought spins are paid for, so the engagement caps do not apply;
+// only an inactive rule stops the grant, in which case zero is returned.
+func (s *BonusSpinService) GrantPurchased(tx *gorm.DB, userID, orderID uuid.UUID, units int) (int, error) {
+	rule, err := s.Rule(tx, models.BonusActionExtraSpinPurchase)
+	if err != nil {
+		return 0, err
+	}
+	if !rule.IsActive || rule.SpinsGranted <= 0 || units <= 0 {
+		return 0, nil
+	}
+
+	grant := models.BonusSpinGrant{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Action:      models.BonusActionExtraSpinPurchase,
+		ReferenceID: orderID,
+		Spins:       rule.SpinsGranted * units,
+	}
+	if err := tx.Create(&grant).Error; err != nil {
+		return 0, err
+	}
+	return grant.Spins, nil
+}
+
+// Available returns how many earned bonus spins the user has left
+func (s *BonusSpinService) Available(db *gorm.DB, userID uuid.UUID) (int, error) {
+	var available int64
+	err := db.Model(&models.BonusSpinGrant{}).
+		Select("COALESCE(SUM(spins - spins_used), 0)").
+		Where("user_id = ?", userID).
+		Scan(&available).Error
+	return int(available), err
+}
+
+// Consume spends one bonus spin from the user's oldest grant, returning false if none are left
+func (s *BonusSpinService) Consume(tx *gorm.DB, userID uuid.UUID) (bool, error) {
+	result := tx.Exec(`
+		UPDATE bonus_spin_grants SET spins_used = spins_used + 1
+		WHERE id = (
+			SELECT id FROM bonus_spin_grants
+			WHERE user_id = ? AND spins_used < spins
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE
+		)`, userID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types published on the EventBus
+const (
+	EventRewardExpired      = "reward.expired"
+	EventCatalogOrderPlaced = "catalog.order_placed"
+	EventEventRegistered    = "event.registered"
+	EventEventCheckedIn     = "event.checked_in"
+	EventUserVerified       = "user.verified"
+	EventNewsRead           = "news.read"
+	EventWaitlistPromoted   = "event.waitlist_promoted"
+)
+
+// Event is a domain event published by one subsystem for others to react to
+type Event struct {
+	Type       string                 `json:"type"`
+	Payload    map[string]interface{} `json:"payload"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// EventHandler reacts to a published event
+type EventHandler func(Event)
+
+// EventBus is an in-process publish/subscribe hub. Handlers run on their own
+// goroutine so a slow or failing subscriber never blocks the publisher.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler)}
+}
+
+// Subscribe registers handler for every event of the given type
+func (b *EventBus) Subscribe(eventType string, handler EventHandler) {
+	b.mu.Lock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.mu.Unlock()
+}
+
+// Publish delivers an event to every subscriber of its type
+func (b *EventBus) Publish(eventType string, payload map[string]interface{}) {
+	event := Event{Type: eventType, Payload: payload, OccurredAt: time.Now()}
+
+	b.mu.RLock()
+	handlers := b.handlers[eventType]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go func(handler EventHandler) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					log.Printf("Event handler for %s panicked: %v", event.Type, recovered)
+				}
+			}()
+			handler(event)
+		}(handler)
+	}
+}
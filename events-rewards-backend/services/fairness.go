@@ -0,0 +1,167 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SpinSeedPeriod is how long one server seed is used before it is revealed
+const SpinSeedPeriod = 24 * time.Hour
+
+var (
+	ErrSeedHashMismatch = errors.New("seed does not match the published hash")
+	ErrEmptySpinPool    = errors.New("spin pool is empty")
+)
+
+// SpinVerification is the outcome of reproducing a spin from its inputs
+type SpinVerification struct {
+	RandomValue      float64   `json:"random_value"`
+	ExpectedRewardID uuid.UUID `json:"expected_reward_id"`
+	Matches          bool      `json:"matches"`
+}
+
+// HashSpinSeed returns the hex SHA-256 of a seed, the value published before the reveal
+func HashSpinSeed(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeriveSpinValue turns a seed, user and nonce into a value in [0, 1). It is
+// HMAC-SHA256 keyed by the seed over "<user_id>:<nonce>", with the first 53
+// bits of the digest divided by 2^53 so the result is an exact float64.
+func DeriveSpinValue(seed string, userID uuid.UUID, nonce int64) float64 {
+	mac := hmac.New(sha256.New, []byte(seed))
+	mac.Write([]byte(userID.String() + ":" + strconv.FormatInt(nonce, 10)))
+	digest := mac.Sum(nil)
+
+	return float64(binary.BigEndian.Uint64(digest[:8])>>11) / (1 << 53)
+}
+
+// PickFromPool returns the index of the pool entry a value in [0, 1) lands on.
+// Entries are walked in order and each covers a share of the pool's total weight.
+func PickFromPool(pool models.SpinPool, value float64) (int, error) {
+	if len(pool) == 0 {
+		return 0, ErrEmptySpinPool
+	}
+
+	var total float64
+	for _, entry := range pool {
+		total += entry.Probability
+	}
+
+	target := value * total
+	var cumulative float64
+	for i, entry := range pool {
+		cumulative += entry.Probability
+		if target < cumulative {
+			return i, nil
+		}
+	}
+
+	return len(pool) - 1, nil
+}
+
+// VerifySpin reproduces a spin from its revealed seed and reports whether it
+// lands on rewardID. If seedHash is not empty the seed is checked against it first.
+func VerifySpin(seed, seedHash string, userID uuid.UUID, nonce int64, pool models.SpinPool, rewardID uuid.UUID) (SpinVerification, error) {
+	if seedHash != "" && !hmac.Equal([]byte(HashSpinSeed(seed)), []byte(seedHash)) {
+		return SpinVerification{}, ErrSeedHashMismatch
+	}
+
+	value := DeriveSpinValue(seed, userID, nonce)
+	index, err := PickFromPool(pool, value)
+	if err != nil {
+		return SpinVerification{}, err
+	}
+
+	return SpinVerification{
+		RandomValue:      value,
+		ExpectedRewardID: pool[index].RewardID,
+		Matches:          pool[index].RewardID == rewardID,
+	}, nil
+}
+
+// SpinSeedService creates a committed seed for each draw period and reveals
+// seeds once their period has ended.
+type SpinSeedService struct {
+	db *gorm.DB
+}
+
+func NewSpinSeedService(db *gorm.DB) *SpinSeedService {
+	return &SpinSeedService{db: db}
+}
+
+// PeriodStart returns the start of the UTC seed period containing now
+func PeriodStart(now time.Time) time.Time {
+	return now.UTC().Truncate(SpinSeedPeriod)
+}
+
+// CurrentSeed returns the seed for the period containing now, creating it on
+// first use. Concurrent callers race on the unique period_start and all read
+// back the same row.
+func (s *SpinSeedService) CurrentSeed(tx *gorm.DB, now time.Time) (*models.SpinSeed, error) {
+	start := PeriodStart(now)
+
+	seedBytes := make([]byte, 32)
+	if _, err := rand.Read(seedBytes); err != nil {
+		return nil, err
+	}
+	seed := hex.EncodeToString(seedBytes)
+
+	candidate := models.SpinSeed{
+		ID:          uuid.New(),
+		PeriodStart: start,
+		PeriodEnd:   start.Add(SpinSeedPeriod),
+		Seed:        seed,
+		SeedHash:    HashSpinSeed(seed),
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&candidate).Error; err != nil {
+		return nil, err
+	}
+
+	var current models.SpinSeed
+	if err := tx.Where("period_start = ?", start).First(&current).Error; err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+// RevealExpired marks every seed whose period has ended as revealed
+func (s *SpinSeedService) RevealExpired(now time.Time) (int64, error) {
+	result := s.db.Model(&models.SpinSeed{}).
+		Where("period_end <= ? AND revealed_at IS NULL", now).
+		Update("revealed_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// StartRevealer reveals seeds whose period has ended, once at startup and then
+// every interval. It is the only place seeds are revealed, so public fairness
+// endpoints stay read-only.
+func (s *SpinSeedService) StartRevealer(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			revealed, err := s.RevealExpired(time.Now())
+			if err != nil {
+				log.Printf("Spin seed reveal failed: %v", err)
+			} else if revealed > 0 {
+				log.Printf("Revealed %d spin seeds", revealed)
+			}
+			<-ticker.C
+		}
+	}()
+}
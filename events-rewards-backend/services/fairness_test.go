@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+
+	"github.com/google/uuid"
+)
+
+func TestDeriveSpinValue(t *testing.T) {
+	userID := uuid.MustParse("6f1c2a9e-3b1d-4c55-9a6e-2f4b8d7e1a30")
+
+	first := DeriveSpinValue("seed", userID, 1)
+	if again := DeriveSpinValue("seed", userID, 1); again != first {
+		t.Errorf("same inputs gave %v and %v", first, again)
+	}
+
+	seen := map[float64]bool{}
+	for nonce := int64(1); nonce <= 1000; nonce++ {
+		value := DeriveSpinValue("seed", userID, nonce)
+		if value < 0 || value >= 1 {
+			t.Fatalf("nonce %d gave %v, want a value in [0, 1)", nonce, value)
+		}
+		seen[value] = true
+	}
+	if len(seen) != 1000 {
+		t.Errorf("1000 nonces gave %d distinct values", len(seen))
+	}
+
+	if DeriveSpinValue("other seed", userID, 1) == first {
+		t.Error("a different seed gave the same value")
+	}
+	if DeriveSpinValue("seed", uuid.New(), 1) == first {
+		t.Error("a different user gave the same value")
+	}
+}
+
+func TestPickFromPool(t *testing.T) {
+	pool := models.SpinPool{
+		{RewardID: uuid.New(), Probability: 0.5},
+		{RewardID: uuid.New(), Probability: 0.3},
+		{RewardID: uuid.New(), Probability: 0.2},
+	}
+
+	tests := []struct {
+		value float64
+		want  int
+	}{
+		{0, 0},
+		{0.4999, 0},
+		{0.5, 1},
+		{0.7999, 1},
+		{0.8, 2},
+		{0.9999, 2},
+	}
+	for _, tt := range tests {
+		got, err := PickFromPool(pool, tt.value)
+		if err != nil {
+			t.Fatalf("PickFromPool(%v): %v", tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("PickFromPool(%v) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+
+	// Weights that don't sum to 1 are scaled to the pool's total
+	scaled := models.SpinPool{{Probability: 0.1}, {Probability: 0.1}}
+	if got, _ := PickFromPool(scaled, 0.6); got != 1 {
+		t.Errorf("PickFromPool on an unnormalized pool = %d, want 1", got)
+	}
+
+	if _, err := PickFromPool(nil, 0.5); !errors.Is(err, ErrEmptySpinPool) {
+		t.Errorf("empty pool returned %v, want ErrEmptySpinPool", err)
+	}
+}
+
+func TestVerifySpin(t *testing.T) {
+	userID := uuid.New()
+	pool := models.SpinPool{
+		{RewardID: uuid.New(), Probability: 0.5},
+		{RewardID: uuid.New(), Probability: 0.5},
+	}
+	index, _ := PickFromPool(pool, DeriveSpinValue("seed", userID, 3))
+	drawn := pool[index].RewardID
+
+	result, err := VerifySpin("seed", HashSpinSeed("seed"), userID, 3, pool, drawn)
+	if err != nil {
+		t.Fatalf("VerifySpin: %v", err)
+	}
+	if !result.Matches || result.ExpectedRewardID != drawn {
+		t.Errorf("VerifySpin = %+v, want a match on %s", result, drawn)
+	}
+
+	if _, err := VerifySpin("seed", HashSpinSeed("forged"), userID, 3, pool, drawn); !errors.Is(err, ErrSeedHashMismatch) {
+		t.Errorf("mismatched hash returned %v, want ErrSeedHashMismatch", err)
+	}
+}
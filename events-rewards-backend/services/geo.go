@@ -0,0 +1,78 @@
+package services
+
+import "math"
+
+// EarthRadiusKm is the mean Earth radius used for distance calculations
+const EarthRadiusKm = 6371.0
+
+// GeoPoint is a latitude/longitude pair in degrees
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// GeoBox is a latitude/longitude rectangle. When MinLongitude is greater than
+// MaxLongitude the box crosses the antimeridian.
+type GeoBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// ValidCoordinates reports whether a latitude and longitude are in range
+func ValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// DistanceKm returns the great-circle distance between two points using the haversine formula
+func DistanceKm(a, b GeoPoint) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBox returns a box containing every point within radiusKm of center.
+// It is a cheap, indexable prefilter; callers still check the exact distance.
+func BoundingBox(center GeoPoint, radiusKm float64) GeoBox {
+	angular := radiusKm / EarthRadiusKm * 180 / math.Pi
+
+	box := GeoBox{
+		MinLatitude:  center.Latitude - angular,
+		MaxLatitude:  center.Latitude + angular,
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+
+	// Near a pole every longitude is in range
+	if box.MinLatitude <= -90 || box.MaxLatitude >= 90 {
+		box.MinLatitude = math.Max(box.MinLatitude, -90)
+		box.MaxLatitude = math.Min(box.MaxLatitude, 90)
+		return box
+	}
+
+	// Meridians converge away from the equator, so the longitude span widens with latitude
+	lngDelta := math.Asin(math.Sin(radiusKm/EarthRadiusKm)/math.Cos(center.Latitude*math.Pi/180)) * 180 / math.Pi
+	if math.IsNaN(lngDelta) || lngDelta >= 180 {
+		return box
+	}
+	box.MinLongitude = wrapLongitude(center.Longitude - lngDelta)
+	box.MaxLongitude = wrapLongitude(center.Longitude + lngDelta)
+
+	return box
+}
+
+// wrapLongitude brings a longitude back into the -180..180 range
+func wrapLongitude(longitude float64) float64 {
+	if longitude < -180 {
+		return longitude + 360
+	}
+	if longitude > 180 {
+		return longitude - 360
+	}
+	return longitude
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b GeoPoint
+		want float64
+	}{
+		{"same point", GeoPoint{51.5074, -0.1278}, GeoPoint{51.5074, -0.1278}, 0},
+		{"London to Paris", GeoPoint{51.5074, -0.1278}, GeoPoint{48.8566, 2.3522}, 343.6},
+		{"across the antimeridian", GeoPoint{0, 179.5}, GeoPoint{0, -179.5}, 111.2},
+		{"antipodes", GeoPoint{0, 0}, GeoPoint{0, 180}, math.Pi * EarthRadiusKm},
+	}
+	for _, tt := range tests {
+		if got := DistanceKm(tt.a, tt.b); math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("%s: DistanceKm = %.1f, want %.1f", tt.name, got, tt.want)
+		}
+		if got, back := DistanceKm(tt.a, tt.b), DistanceKm(tt.b, tt.a); math.Abs(got-back) > 1e-9 {
+			t.Errorf("%s: distance is not symmetric: %v and %v", tt.name, got, back)
+		}
+	}
+}
+
+// destination returns the point distanceKm from start along the initial bearing in degrees
+func destination(start GeoPoint, bearing, distanceKm float64) GeoPoint {
+	lat1 := start.Latitude * math.Pi / 180
+	lng1 := start.Longitude * math.Pi / 180
+	theta := bearing * math.Pi / 180
+	delta := distanceKm / EarthRadiusKm
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	return GeoPoint{Latitude: lat2 * 180 / math.Pi, Longitude: wrapLongitude(lng2 * 180 / math.Pi)}
+}
+
+// boxContains reports whether p lies inside box, allowing for boxes that cross the antimeridian
+func boxContains(box GeoBox, p GeoPoint) bool {
+	const epsilon = 1e-9
+	if p.Latitude < box.MinLatitude-epsilon || p.Latitude > box.MaxLatitude+epsilon {
+		return false
+	}
+	if box.MinLongitude <= box.MaxLongitude {
+		return p.Longitude >= box.MinLongitude-epsilon && p.Longitude <= box.MaxLongitude+epsilon
+	}
+	return p.Longitude >= box.MinLongitude-epsilon || p.Longitude <= box.MaxLongitude+epsilon
+}
+
+func TestBoundingBoxContainsRadius(t *testing.T) {
+	centers := []struct {
+		name     string
+		center   GeoPoint
+		radiusKm float64
+	}{
+		{"equator", GeoPoint{0, 0}, 100},
+		{"mid latitude", GeoPoint{51.5074, -0.1278}, 25},
+		{"high latitude", GeoPoint{69.6492, 18.9553}, 300},
+		{"southern hemisphere", GeoPoint{-33.8688, 151.2093}, 50},
+		{"antimeridian", GeoPoint{-17.7134, 179.9}, 40},
+	}
+
+	for _, tt := range centers {
+		box := BoundingBox(tt.center, tt.radiusKm)
+		for bearing := 0.0; bearing < 360; bearing += 5 {
+			edge := destination(tt.center, bearing, tt.radiusKm)
+			if !boxContains(box, edge) {
+				t.Errorf("%s: point %v on the radius at bearing %v is outside %+v", tt.name, edge, bearing, box)
+				break
+			}
+		}
+	}
+}
+
+func TestBoundingBoxEdges(t *testing.T) {
+	crossing := BoundingBox(GeoPoint{0, 179.9}, 50)
+	if crossing.MinLongitude <= crossing.MaxLongitude {
+		t.Errorf("box at the antimeridian should wrap, got %+v", crossing)
+	}
+
+	polar := BoundingBox(GeoPoint{89.9, 10}, 50)
+	if polar.MaxLatitude != 90 || polar.MinLongitude != -180 || polar.MaxLongitude != 180 {
+		t.Errorf("box around the pole should span every longitude up to 90, got %+v", polar)
+	}
+
+	// Boxes widen in longitude away from the equator
+	equator := BoundingBox(GeoPoint{0, 0}, 100)
+	north := BoundingBox(GeoPoint{60, 0}, 100)
+	if north.MaxLongitude-north.MinLongitude <= equator.MaxLongitude-equator.MinLongitude {
+		t.Errorf("box at 60N (%+v) is not wider than at the equator (%+v)", north, equator)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+)
+
+// CalendarEventDuration is the length given to events in calendar exports.
+// Events only have a start time, so clients need a sensible default block.
+const CalendarEventDuration = 2 * time.Hour
+
+// calendarUIDDomain makes event UIDs globally unique, as RFC 5545 requires
+const calendarUIDDomain = "events-rewards"
+
+const icalTimeFormat = "20060102T150405Z"
+
+// ICalendar builds an RFC 5545 calendar. Times are written in UTC, which every
+// client converts to the viewer's zone, so no VTIMEZONE definitions are needed.
+type ICalendar struct {
+	builder strings.Builder
+}
+
+// NewICalendar starts a calendar with the given display name
+func NewICalendar(name string) *ICalendar {
+	cal := &ICalendar{}
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//Events Rewards//Events//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.line("X-WR-CALNAME:" + escapeICalText(name))
+	return cal
+}
+
+// AddEvent writes event as a VEVENT. The UID is stable across exports and
+// SEQUENCE follows the event's edit count, so subscribed clients update the
+// entry in place when the event changes.
+func (c *ICalendar) AddEvent(event *models.Event) {
+	c.line("BEGIN:VEVENT")
+	c.line("UID:" + event.ID.String() + "@" + calendarUIDDomain)
+	c.line("DTSTAMP:" + time.Now().UTC().Format(icalTimeFormat))
+	c.line("DTSTART:" + event.EventDate.UTC().Format(icalTimeFormat))
+	c.line("DTEND:" + event.EventDate.Add(CalendarEventDuration).UTC().Format(icalTimeFormat))
+	c.line("SEQUENCE:" + strconv.Itoa(event.Sequence))
+	c.line("LAST-MODIFIED:" + event.UpdatedAt.UTC().Format(icalTimeFormat))
+	c.line("SUMMARY:" + escapeICalText(event.Title))
+	if event.Description != nil && *event.Description != "" {
+		c.line("DESCRIPTION:" + escapeICalText(*event.Description))
+	}
+	if location := eventLocationText(event); location != "" {
+		c.line("LOCATION:" + escapeICalText(location))
+	}
+	if event.Latitude != nil && event.Longitude != nil {
+		c.line("GEO:" + strconv.FormatFloat(*event.Latitude, 'f', 6, 64) + ";" + strconv.FormatFloat(*event.Longitude, 'f', 6, 64))
+	}
+	if event.Category != nil && *event.Category != "" {
+		c.line("CATEGORIES:" + escapeICalText(*event.Category))
+	}
+	if event.IsActive {
+		c.line("STATUS:CONFIRMED")
+	} else {
+		c.line("STATUS:CANCELLED")
+	}
+	c.line("END:VEVENT")
+}
+
+// Bytes finishes the calendar and returns it
+func (c *ICalendar) Bytes() []byte {
+	c.line("END:VCALENDAR")
+	return []byte(c.builder.String())
+}
+
+// eventLocationText combines an event's venue and location into one line
+func eventLocationText(event *models.Event) string {
+	var parts []string
+	if event.Venue != nil && *event.Venue != "" {
+		parts = append(parts, *event.Venue)
+	}
+	if event.Location != nil && *event.Location != "" {
+		parts = append(parts, *event.Location)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// line writes a content line, folding it at 75 octets without splitting a UTF-8 character
+func (c *ICalendar) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+		c.builder.WriteString(content[:cut])
+		c.builder.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = 74
+	}
+	c.builder.WriteString(content)
+	c.builder.WriteString("\r\n")
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// escapeICalText escapes a TEXT property value
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
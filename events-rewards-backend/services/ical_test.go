@@ -0,0 +1,102 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+
+	"github.com/google/uuid"
+)
+
+func TestICalendarLineFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"short", "SUMMARY:Meetup"},
+		{"exactly one line", "SUMMARY:" + strings.Repeat("a", 75-len("SUMMARY:"))},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		// Three-octet characters never line up with the fold points
+		{"multibyte", "DESCRIPTION:" + strings.Repeat("日本語", 40)},
+	}
+
+	for _, tt := range tests {
+		cal := &ICalendar{}
+		cal.line(tt.content)
+		out := cal.builder.String()
+
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%s: output does not end with CRLF", tt.name)
+		}
+		for _, physical := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			if len(physical) > 75 {
+				t.Errorf("%s: line of %d octets exceeds 75", tt.name, len(physical))
+			}
+			if !utf8.ValidString(physical) {
+				t.Errorf("%s: fold split a UTF-8 character: %q", tt.name, physical)
+			}
+		}
+
+		// Unfolding removes each CRLF and the single space after it
+		if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.content {
+			t.Errorf("%s: unfolded line = %q, want %q", tt.name, unfolded, tt.content)
+		}
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{`back\slash`, `back\\slash`},
+		{"a;b,c", `a\;b\,c`},
+		{"line one\r\nline two\nline three", `line one\nline two\nline three`},
+	}
+	for _, tt := range tests {
+		if got := escapeICalText(tt.value); got != tt.want {
+			t.Errorf("escapeICalText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestICalendarAddEvent(t *testing.T) {
+	description := "Bring snacks; drinks, too"
+	venue := "Hall A"
+	event := models.Event{
+		ID:          uuid.MustParse("0b7c8f5e-1d2a-4e3b-9c4d-5e6f7a8b9c0d"),
+		Title:       "Launch party",
+		Description: &description,
+		Venue:       &venue,
+		EventDate:   time.Date(2025, 6, 1, 18, 30, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		Sequence:    3,
+		IsActive:    false,
+	}
+
+	cal := NewICalendar("My events")
+	cal.AddEvent(&event)
+	out := string(cal.Bytes())
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:My events\r\n",
+		"UID:0b7c8f5e-1d2a-4e3b-9c4d-5e6f7a8b9c0d@events-rewards\r\n",
+		"DTSTART:20250601T163000Z\r\n",
+		"DTEND:20250601T183000Z\r\n",
+		"SEQUENCE:3\r\n",
+		`DESCRIPTION:Bring snacks\; drinks\, too` + "\r\n",
+		"LOCATION:Hall A\r\n",
+		"STATUS:CANCELLED\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar is missing %q", strings.TrimSuffix(want, "\r\n"))
+		}
+	}
+	if !strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n") {
+		t.Error("calendar does not end with the event and calendar terminators")
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyTTL is how long a stored response can be replayed
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyService stores the first response for each idempotency key in the
+// idempotency_keys table. It implements middleware.IdempotencyStore.
+type IdempotencyService struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyService(db *gorm.DB) *IdempotencyService {
+	return &IdempotencyService{db: db}
+}
+
+// Reserve claims the key for a new request. If the key is already taken it
+// returns the existing record and false; expired records are replaced.
+func (s *IdempotencyService) Reserve(scope, key, fingerprint string) (*models.IdempotencyKey, bool, error) {
+	now := time.Now()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		record := models.IdempotencyKey{
+			ID:          uuid.New(),
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(IdempotencyKeyTTL),
+		}
+
+		result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return &record, true, nil
+		}
+
+		var existing models.IdempotencyKey
+		if err := s.db.Where("scope = ? AND key = ?", scope, key).First(&existing).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue
+			}
+			return nil, false, err
+		}
+		if now.Before(existing.ExpiresAt) {
+			return &existing, false, nil
+		}
+
+		// The stored response is too old to replay; drop it and claim the key again
+		if err := s.db.Where("id = ?", existing.ID).Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return nil, false, err
+		}
+	}
+
+	return nil, false, gorm.ErrRecordNotFound
+}
+
+// Complete stores the response for a reserved key so it can be replayed
+func (s *IdempotencyService) Complete(scope, key string, statusCode int, contentType string, body []byte) error {
+	return s.db.Model(&models.IdempotencyKey{}).
+		Where("scope = ? AND key = ?", scope, key).
+		Updates(map[string]interface{}{
+			"completed":    true,
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"body":         body,
+		}).Error
+}
+
+// Release frees a reserved key without storing a response, so the request can be retried
+func (s *IdempotencyService) Release(scope, key string) error {
+	return s.db.Where("scope = ? AND key = ? AND completed = ?", scope, key, false).Delete(&models.IdempotencyKey{}).Error
+}
+
+// PurgeExpired deletes stored responses that can no longer be replayed
+func (s *IdempotencyService) PurgeExpired() (int64, error) {
+	result := s.db.Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// StartSweeper periodically purges expired idempotency keys in the background
+func (s *IdempotencyService) StartSweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := s.PurgeExpired()
+			if err != nil {
+				log.Printf("Idempotency key sweep failed: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Idempotency key sweep removed %d expired keys", purged)
+			}
+		}
+	}()
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientPoints  = errors.New("insufficient points")
+	ErrUnbalancedPosting   = errors.New("points postings do not balance")
+	ErrInvalidPointsAmount = errors.New("points amount must be positive")
+)
+
+// PointsPosting is one leg of a transaction before it is written
+type PointsPosting struct {
+	AccountID uuid.UUID
+	Amount    int64
+}
+
+// PointsMismatch describes a reconciliation failure
+type PointsMismatch struct {
+	AccountID     *uuid.UUID `json:"account_id,omitempty"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+	UserID        *uuid.UUID `json:"user_id,omitempty"`
+	Expected      int64      `json:"expected"`
+	Actual        int64      `json:"actual"`
+	Problem       string     `json:"problem"`
+}
+
+// PointsLedger is a double-entry ledger of user points. Every transaction
+// moves points between at least two accounts and its entries sum to zero;
+// user credits are balanced by the rewards pool and debits by redemptions.
+// Only user balances are stored. System balances are derived from their
+// entries, so postings never contend on the shared system account rows.
+type PointsLedger struct {
+	db *gorm.DB
+}
+
+func NewPointsLedger(db *gorm.DB) *PointsLedger {
+	return &PointsLedger{db: db}
+}
+
+// UserAccount returns the user's points account, creating it on first use
+func (l *PointsLedger) UserAccount(tx *gorm.DB, userID uuid.UUID) (*models.PointsAccount, error) {
+	account := models.PointsAccount{ID: uuid.New(), Kind: models.PointsAccountUser, UserID: &userID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, err
+	}
+
+	var existing models.PointsAccount
+	if err := tx.Where("kind = ? AND user_id = ?", models.PointsAccountUser, userID).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// SystemAccount returns the system account of the given kind, creating it on first use
+func (l *PointsLedger) SystemAccount(tx *gorm.DB, kind string) (*models.PointsAccount, error) {
+	account := models.PointsAccount{ID: uuid.New(), Kind: kind}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, err
+	}
+
+	var existing models.PointsAccount
+	if err := tx.Where("kind = ? AND user_id IS NULL", kind).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// Post writes a balanced transaction and updates user account balances. It
+// returns false without writing anything if a transaction with the same kind
+// and reference already exists. User accounts may never go below zero.
+func (l *PointsLedger) Post(tx *gorm.DB, kind, referenceType string, referenceID uuid.UUID, description string, postings []PointsPosting) (bool, error) {
+	var sum int64
+	for _, posting := range postings {
+		sum += posting.Amount
+	}
+	if len(postings) < 2 || sum != 0 {
+		return false, ErrUnbalancedPosting
+	}
+
+	now := time.Now()
+	transaction := models.PointsTransaction{
+		ID:            uuid.New(),
+		Kind:          kind,
+		ReferenceType: referenceType,
+		ReferenceID:   referenceID,
+		Description:   description,
+		CreatedAt:     now,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&transaction)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	for _, posting := range postings {
+		var account models.PointsAccount
+		if err := tx.Where("id = ?", posting.AccountID).First(&account).Error; err != nil {
+			return false, err
+		}
+
+		// System accounts keep no stored balance; their entries record 0
+		var balanceAfter int64
+		if account.Kind == models.PointsAccountUser {
+			// One conditional update both applies the amount and enforces the floor
+			result := tx.Raw(`UPDATE points_accounts SET balance = balance + ?, updated_at = ?
+				WHERE id = ? AND balance + ? >= 0
+				RETURNING balance`, posting.Amount, now, account.ID, posting.Amount).Scan(&balanceAfter)
+			if result.Error != nil {
+				return false, result.Error
+			}
+			if result.RowsAffected == 0 {
+				return false, ErrInsufficientPoints
+			}
+		}
+
+		entry := models.PointsEntry{
+			ID:            uuid.New(),
+			TransactionID: transaction.ID,
+			AccountID:     account.ID,
+			Amount:        posting.Amount,
+			BalanceAfter:  balanceAfter,
+			CreatedAt:     now,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Credit moves points from the rewards pool into the user's account
+func (l *PointsLedger) Credit(tx *gorm.DB, userID uuid.UUID, amount int64, kind, referenceType string, referenceID uuid.UUID, description string) (bool, error) {
+	return l.transfer(tx, userID, amount, models.PointsAccountRewardsPool, kind, referenceType, referenceID, description)
+}
+
+// Debit moves points from the user's account to redemptions, failing with
+// ErrInsufficientPoints if the balance does not cover it
+func (l *PointsLedger) Debit(tx *gorm.DB, userID uuid.UUID, amount int64, kind, referenceType string, referenceID uuid.UUID, description string) (bool, error) {
+	return l.transfer(tx, userID, -amount, models.PointsAccountRedemptions, kind, referenceType, referenceID, description)
+}
+
+// Refund returns previously debited points from redemptions to the user's account
+func (l *PointsLedger) Refund(tx *gorm.DB, userID uuid.UUID, amount int64, kind, referenceType string, referenceID uuid.UUID, description string) (bool, error) {
+	return l.transfer(tx, userID, amount, models.PointsAccountRedemptions, kind, referenceType, referenceID, description)
+}
+
+// transfer posts userAmount to the user's account and the opposite amount to a system account
+func (l *PointsLedger) transfer(tx *gorm.DB, userID uuid.UUID, userAmount int64, systemKind, kind, referenceType string, referenceID uuid.UUID, description string) (bool, error) {
+	if userAmount == 0 {
+		return false, ErrInvalidPointsAmount
+	}
+
+	userAccount, err := l.UserAccount(tx, userID)
+	if err != nil {
+		return false, err
+	}
+	systemAccount, err := l.SystemAccount(tx, systemKind)
+	if err != nil {
+		return false, err
+	}
+
+	return l.Post(tx, kind, referenceType, referenceID, description, []PointsPosting{
+		{AccountID: userAccount.ID, Amount: userAmount},
+		{AccountID: systemAccount.ID, Amount: -userAmount},
+	})
+}
+
+// Balance returns the user's current points balance
+func (l *PointsLedger) Balance(userID uuid.UUID) (int64, error) {
+	var accounts []models.PointsAccount
+	if err := l.db.Where("kind = ? AND user_id = ?", models.PointsAccountUser, userID).Limit(1).Find(&accounts).Error; err != nil {
+		return 0, err
+	}
+	if len(accounts) == 0 {
+		return 0, nil
+	}
+	return accounts[0].Balance, nil
+}
+
+// Reconcile checks that every transaction balances and that every user
+// account's stored balance matches the sum of its entries, returning any mismatches
+func (l *PointsLedger) Reconcile() ([]PointsMismatch, error) {
+	mismatches := []PointsMismatch{}
+
+	var unbalanced []struct {
+		TransactionID uuid.UUID
+		Total         int64
+	}
+	if err := l.db.Model(&models.PointsEntry{}).
+		Select("transaction_id, SUM(amount) AS total").
+		Group("transaction_id").
+		Having("SUM(amount) <> 0").
+		Scan(&unbalanced).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range unbalanced {
+		transactionID := row.TransactionID
+		mismatches = append(mismatches, PointsMismatch{
+			TransactionID: &transactionID,
+			Expected:      0,
+			Actual:        row.Total,
+			Problem:       "transaction entries do not sum to zero",
+		})
+	}
+
+	var drifted []struct {
+		ID      uuid.UUID
+		UserID  *uuid.UUID
+		Balance int64
+		Ledger  int64
+	}
+	if err := l.db.Table("points_accounts").
+		Select("points_accounts.id, points_accounts.user_id, points_accounts.balance, COALESCE(SUM(points_entries.amount), 0) AS ledger").
+		Joins("LEFT JOIN points_entries ON points_entries.account_id = points_accounts.id").
+		Where("points_accounts.kind = ?", models.PointsAccountUser).
+		Group("points_accounts.id").
+		Having("points_accounts.balance <> COALESCE(SUM(points_entries.amount), 0)").
+		Scan(&drifted).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range drifted {
+		accountID := row.ID
+		mismatches = append(mismatches, PointsMismatch{
+			AccountID: &accountID,
+			UserID:    row.UserID,
+			Expected:  row.Ledger,
+			Actual:    row.Balance,
+			Problem:   fmt.Sprintf("stored balance is off by %d", row.Balance-row.Ledger),
+		})
+	}
+
+	return mismatches, nil
+}
+
+// SystemBalance returns the balance of a system account, the sum of its entries
+func (l *PointsLedger) SystemBalance(kind string) (int64, error) {
+	var balance int64
+	err := l.db.Model(&models.PointsEntry{}).
+		Joins("JOIN points_accounts ON points_accounts.id = points_entries.account_id").
+		Where("points_accounts.kind = ? AND points_accounts.user_id IS NULL", kind).
+		Select("COALESCE(SUM(points_entries.amount), 0)").
+		Scan(&balance).Error
+	return balance, err
+}
+
+// BackfillClaimedRewards credits points rewards that were claimed before the
+// ledger existed. Credits use the same kind and reference as a live claim, so
+// running it again, or alongside new claims, never pays a reward twice.
+func (l *PointsLedger) BackfillClaimedRewards() (int, error) {
+	var claims []struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+		Name   string
+		Value  float64
+	}
+	if err := l.db.Table("user_rewards").
+		Select("user_rewards.id, user_rewards.user_id, rewards.name, rewards.value").
+		Joins("JOIN rewards ON rewards.id = user_rewards.reward_id").
+		Where("user_rewards.status = ? AND rewards.reward_type = ? AND rewards.value >= 0.5", "claimed", models.RewardTypePoints).
+		Where("NOT EXISTS (SELECT 1 FROM points_transactions WHERE points_transactions.kind = ? AND points_transactions.reference_id = user_rewards.id)", models.PointsTxnRewardCredit).
+		Scan(&claims).Error; err != nil {
+		return 0, err
+	}
+
+	credited := 0
+	for _, claim := range claims {
+		err := l.db.Transaction(func(tx *gorm.DB) error {
+			posted, err := l.Credit(tx, claim.UserID, int64(math.Round(claim.Value)), models.PointsTxnRewardCredit, "user_reward", claim.ID, "Claimed "+claim.Name)
+			if posted {
+				credited++
+			}
+			return err
+		})
+		if err != nil {
+			log.Printf("Points backfill failed for user reward %s: %v", claim.ID, err)
+		}
+	}
+	return credited, nil
+}
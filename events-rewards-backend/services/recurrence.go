@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+)
+
+// MaxSeriesOccurrences bounds how many events a single series can materialize
+const MaxSeriesOccurrences = 200
+
+// maxRecurrenceSteps stops runaway expansion of rules that rarely match, such
+// as a monthly series on the 31st with a large interval
+const maxRecurrenceSteps = 5000
+
+var ErrTooManyOccurrences = errors.New("series has too many occurrences")
+
+// RecurrenceWeekdays maps RRULE BYDAY codes to weekdays
+var RecurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ExpandRecurrence returns the start time of every occurrence of a series, in
+// order. Times are computed in the series' time zone, so a weekly 7pm meetup
+// stays at 7pm local time across daylight saving changes. As in RRULE, COUNT
+// includes occurrences that are then removed as exceptions.
+func ExpandRecurrence(series *models.EventSeries) ([]time.Time, error) {
+	loc, err := time.LoadLocation(series.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	start := series.StartsAt.In(loc)
+	interval := series.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var occurrences []time.Time
+	generated := 0
+
+	// emit records one candidate occurrence and reports whether expansion should continue
+	emit := func(candidate time.Time) (bool, error) {
+		if series.Until != nil && candidate.After(*series.Until) {
+			return false, nil
+		}
+		generated++
+		if !series.Exceptions.Contains(candidate.Format("2006-01-02")) {
+			if len(occurrences) == MaxSeriesOccurrences {
+				return false, ErrTooManyOccurrences
+			}
+			occurrences = append(occurrences, candidate)
+		}
+		return series.Count == nil || generated < *series.Count, nil
+	}
+
+	for step := 0; step < maxRecurrenceSteps; step++ {
+		var candidates []time.Time
+
+		switch series.Frequency {
+		case models.RecurrenceDaily:
+			candidates = []time.Time{start.AddDate(0, 0, step*interval)}
+		case models.RecurrenceWeekly:
+			candidates = weeklyCandidates(start, series.ByWeekday, step*interval)
+		case models.RecurrenceMonthly:
+			// Months without the start's day of month are skipped, as RRULE does
+			candidate := time.Date(start.Year(), start.Month()+time.Month(step*interval), start.Day(),
+				start.Hour(), start.Minute(), start.Second(), 0, loc)
+			if candidate.Day() == start.Day() {
+				candidates = []time.Time{candidate}
+			}
+		default:
+			return nil, errors.New("unsupported recurrence frequency")
+		}
+
+		for _, candidate := range candidates {
+			more, err := emit(candidate)
+			if err != nil {
+				return nil, err
+			}
+			if !more {
+				return occurrences, nil
+			}
+		}
+	}
+
+	return occurrences, nil
+}
+
+// weeklyCandidates returns the occurrences in the week weekOffset weeks after
+// the start's week, on the given BYDAY weekdays or the start's own weekday
+func weeklyCandidates(start time.Time, byWeekday models.StringList, weekOffset int) []time.Time {
+	weekdays := []time.Weekday{start.Weekday()}
+	if len(byWeekday) > 0 {
+		weekdays = weekdays[:0]
+		for _, code := range byWeekday {
+			if weekday, ok := RecurrenceWeekdays[code]; ok {
+				weekdays = append(weekdays, weekday)
+			}
+		}
+	}
+
+	// Weeks start on Monday, the RRULE default for WKST
+	sinceMonday := (int(start.Weekday()) + 6) % 7
+	monday := start.AddDate(0, 0, weekOffset*7-sinceMonday)
+
+	var candidates []time.Time
+	for _, weekday := range weekdays {
+		candidate := monday.AddDate(0, 0, (int(weekday)+6)%7)
+		if candidate.Before(start) {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	return candidates
+}
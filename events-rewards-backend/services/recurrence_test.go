@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+)
+
+func intPtr(v int) *int { return &v }
+
+// formatOccurrences renders occurrences in their own zone for readable comparisons
+func formatOccurrences(occurrences []time.Time) []string {
+	formatted := make([]string, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		formatted = append(formatted, occurrence.Format("2006-01-02 15:04 MST"))
+	}
+	return formatted
+}
+
+func TestExpandRecurrence(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load time zone: %v", err)
+	}
+	until := time.Date(2025, 1, 20, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		series models.EventSeries
+		want   []string
+	}{
+		{
+			name: "daily with count",
+			series: models.EventSeries{
+				StartsAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Timezone: "UTC",
+				Frequency: models.RecurrenceDaily, Interval: 2, Count: intPtr(3),
+			},
+			want: []string{"2025-01-01 09:00 UTC", "2025-01-03 09:00 UTC", "2025-01-05 09:00 UTC"},
+		},
+		{
+			name: "weekly by weekday until a date",
+			series: models.EventSeries{
+				// A Wednesday, so the Monday of the first week is skipped
+				StartsAt: time.Date(2025, 1, 8, 18, 0, 0, 0, time.UTC), Timezone: "UTC",
+				Frequency: models.RecurrenceWeekly, Interval: 1,
+				ByWeekday: models.StringList{"FR", "MO", "WE"}, Until: &until,
+			},
+			want: []string{
+				"2025-01-08 18:00 UTC", "2025-01-10 18:00 UTC",
+				"2025-01-13 18:00 UTC", "2025-01-15 18:00 UTC", "2025-01-17 18:00 UTC",
+				"2025-01-20 18:00 UTC",
+			},
+		},
+		{
+			name: "weekly keeps local time across daylight saving",
+			series: models.EventSeries{
+				StartsAt: time.Date(2025, 3, 3, 19, 0, 0, 0, newYork), Timezone: "America/New_York",
+				Frequency: models.RecurrenceWeekly, Interval: 1, Count: intPtr(2),
+			},
+			want: []string{"2025-03-03 19:00 EST", "2025-03-10 19:00 EDT"},
+		},
+		{
+			name: "monthly skips months without the day",
+			series: models.EventSeries{
+				StartsAt: time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC), Timezone: "UTC",
+				Frequency: models.RecurrenceMonthly, Interval: 1, Count: intPtr(3),
+			},
+			want: []string{"2025-01-31 12:00 UTC", "2025-03-31 12:00 UTC", "2025-05-31 12:00 UTC"},
+		},
+		{
+			name: "exceptions still count towards count",
+			series: models.EventSeries{
+				StartsAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Timezone: "UTC",
+				Frequency: models.RecurrenceDaily, Interval: 1, Count: intPtr(3),
+				Exceptions: models.StringList{"2025-01-02"},
+			},
+			want: []string{"2025-01-01 09:00 UTC", "2025-01-03 09:00 UTC"},
+		},
+	}
+
+	for _, tt := range tests {
+		occurrences, err := ExpandRecurrence(&tt.series)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got := formatOccurrences(occurrences)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestExpandRecurrenceLimits(t *testing.T) {
+	unbounded := models.EventSeries{
+		StartsAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), Timezone: "UTC",
+		Frequency: models.RecurrenceDaily, Interval: 1,
+	}
+	if _, err := ExpandRecurrence(&unbounded); !errors.Is(err, ErrTooManyOccurrences) {
+		t.Errorf("unbounded series returned %v, want ErrTooManyOccurrences", err)
+	}
+
+	unknown := unbounded
+	unknown.Frequency = "YEARLY"
+	unknown.Count = intPtr(1)
+	if _, err := ExpandRecurrence(&unknown); err == nil {
+		t.Error("unsupported frequency returned no error")
+	}
+
+	badZone := unbounded
+	badZone.Timezone = "Not/AZone"
+	badZone.Count = intPtr(1)
+	if _, err := ExpandRecurrence(&badZone); err == nil {
+		t.Error("unknown time zone returned no error")
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRedemptionTokenRoundTrip(t *testing.T) {
+	tokens := NewRedemptionTokenService("test-secret")
+	userRewardID := uuid.New()
+
+	token := tokens.Sign(userRewardID, "ABC123")
+	gotID, gotCode, err := tokens.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if gotID != userRewardID || gotCode != "ABC123" {
+		t.Errorf("Verify = %s, %q, want %s, %q", gotID, gotCode, userRewardID, "ABC123")
+	}
+
+	// Scanners may pass along surrounding whitespace
+	if _, _, err := tokens.Verify("  " + token + "\n"); err != nil {
+		t.Errorf("Verify with surrounding whitespace: %v", err)
+	}
+}
+
+func TestRedemptionTokenRejectsTampering(t *testing.T) {
+	tokens := NewRedemptionTokenService("test-secret")
+	userRewardID := uuid.New()
+	token := tokens.Sign(userRewardID, "ABC123")
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"missing signature", parts[0] + "." + parts[1]},
+		{"extra part", token + ".extra"},
+		{"changed claim code", parts[0] + ".XYZ789." + parts[2]},
+		{"changed reward", uuid.New().String() + "." + parts[1] + "." + parts[2]},
+		{"changed signature", parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2]))},
+		{"other secret", NewRedemptionTokenService("other-secret").Sign(userRewardID, "ABC123")},
+		{"not a uuid", "not-a-uuid." + parts[1] + "." + NewRedemptionTokenService("test-secret").signature("not-a-uuid."+parts[1])},
+	}
+	for _, tt := range tests {
+		if _, _, err := tokens.Verify(tt.token); !errors.Is(err, ErrInvalidRedemptionToken) {
+			t.Errorf("%s: Verify returned %v, want ErrInvalidRedemptionToken", tt.name, err)
+		}
+	}
+}
+
+func TestRedemptionQRCode(t *testing.T) {
+	png, err := NewRedemptionTokenService("test-secret").QRCode(uuid.New(), "ABC123")
+	if err != nil {
+		t.Fatalf("QRCode: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("QRCode did not return a PNG")
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rewardExpiryBatchSize bounds how many rows one expiry transaction locks
+const rewardExpiryBatchSize = 500
+
+// RewardExpiryService moves pending user rewards past their expiry to the
+// expired status, returns their stock to the catalog and publishes
+// EventRewardExpired for each one.
+type RewardExpiryService struct {
+	db  *gorm.DB
+	bus *EventBus
+}
+
+func NewRewardExpiryService(db *gorm.DB, bus *EventBus) *RewardExpiryService {
+	return &RewardExpiryService{db: db, bus: bus}
+}
+
+// ExpireOverdue expires every pending reward whose expiry is before now and
+// returns how many were expired. Rows are taken with SKIP LOCKED, so several
+// instances can run the job at once without double counting stock.
+func (s *RewardExpiryService) ExpireOverdue(now time.Time) (int, error) {
+	total := 0
+
+	for {
+		var expired []models.UserReward
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", "pending", now).
+				Order("expires_at").
+				Limit(rewardExpiryBatchSize).
+				Find(&expired).Error; err != nil {
+				return err
+			}
+			if len(expired) == 0 {
+				return nil
+			}
+
+			ids := make([]uuid.UUID, len(expired))
+			returned := make(map[uuid.UUID]int)
+			for i, userReward := range expired {
+				ids[i] = userReward.ID
+				returned[userReward.RewardID]++
+			}
+
+			if err := tx.Model(&models.UserReward{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{"status": "expired", "updated_at": now}).Error; err != nil {
+				return err
+			}
+
+			// Give the unclaimed units back so they can be won again
+			for rewardID, count := range returned {
+				if err := tx.Model(&models.Reward{}).
+					Where("id = ?", rewardID).
+					Update("total_claimed", gorm.Expr("GREATEST(total_claimed - ?, 0)", count)).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, userReward := range expired {
+			s.bus.Publish(EventRewardExpired, map[string]interface{}{
+				"user_reward_id": userReward.ID,
+				"user_id":        userReward.UserID,
+				"reward_id":      userReward.RewardID,
+				"campaign_id":    userReward.CampaignID,
+				"expired_at":     userReward.ExpiresAt,
+			})
+		}
+
+		total += len(expired)
+		if len(expired) < rewardExpiryBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// StartWorker periodically expires overdue rewards in the background
+func (s *RewardExpiryService) StartWorker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := s.ExpireOverdue(time.Now())
+			if err != nil {
+				log.Printf("Reward expiry run failed: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Expired %d unclaimed rewards", expired)
+			}
+		}
+	}()
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTicketTokenRoundTrip(t *testing.T) {
+	tickets := NewTicketTokenService("test-secret")
+	registrationID, eventID := uuid.New(), uuid.New()
+
+	token := tickets.Sign(registrationID, eventID)
+	gotRegistration, gotEvent, err := tickets.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if gotRegistration != registrationID || gotEvent != eventID {
+		t.Errorf("Verify = %s, %s, want %s, %s", gotRegistration, gotEvent, registrationID, eventID)
+	}
+
+	// Scanners may pass along surrounding whitespace
+	if _, _, err := tickets.Verify("  " + token + "\n"); err != nil {
+		t.Errorf("Verify with surrounding whitespace: %v", err)
+	}
+}
+
+func TestTicketTokenRejectsTampering(t *testing.T) {
+	tickets := NewTicketTokenService("test-secret")
+	registrationID, eventID := uuid.New(), uuid.New()
+	token := tickets.Sign(registrationID, eventID)
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"missing signature", parts[0] + "." + parts[1]},
+		{"extra part", token + ".extra"},
+		{"changed event", parts[0] + "." + uuid.New().String() + "." + parts[2]},
+		{"changed registration", uuid.New().String() + "." + parts[1] + "." + parts[2]},
+		{"swapped ids", parts[1] + "." + parts[0] + "." + parts[2]},
+		{"changed signature", parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2]))},
+		{"other secret", NewTicketTokenService("other-secret").Sign(registrationID, eventID)},
+		{"not a uuid", "not-a-uuid." + parts[1] + "." + tickets.signature("not-a-uuid."+parts[1])},
+	}
+	for _, tt := range tests {
+		if _, _, err := tickets.Verify(tt.token); !errors.Is(err, ErrInvalidTicketToken) {
+			t.Errorf("%s: Verify returned %v, want ErrInvalidTicketToken", tt.name, err)
+		}
+	}
+}
+
+func TestTicketTokenRejectsRedemptionToken(t *testing.T) {
+	// Both services may share a secret, so a redemption token shaped like a
+	// ticket must still fail because each signs under its own prefix
+	registrationID, eventID := uuid.New(), uuid.New()
+	token := NewRedemptionTokenService("shared-secret").Sign(registrationID, eventID.String())
+
+	if _, _, err := NewTicketTokenService("shared-secret").Verify(token); !errors.Is(err, ErrInvalidTicketToken) {
+		t.Errorf("Verify accepted a redemption token: %v", err)
+	}
+}
+
+func TestTicketQRCode(t *testing.T) {
+	png, err := NewTicketTokenService("test-secret").QRCode(uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("QRCode: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("QRCode did not return a PNG")
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notRevokedCacheTTL bounds how long a negative lookup is trusted before the
+// database is consulted again, so revocations made by other instances are
+// picked up quickly.
+const notRevokedCacheTTL = 30 * time.Second
+
+type blacklistEntry struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+// TokenBlacklistService revokes access tokens by jti and answers revocation
+// lookups from an in-memory cache backed by the token_blacklist table.
+type TokenBlacklistService struct {
+	db    *gorm.DB
+	mu    sync.RWMutex
+	cache map[string]blacklistEntry
+}
+
+func NewTokenBlacklistService(db *gorm.DB) *TokenBlacklistService {
+	return &TokenBlacklistService{
+		db:    db,
+		cache: make(map[string]blacklistEntry),
+	}
+}
+
+// Revoke blacklists the token with the given jti until it would have expired anyway
+func (s *TokenBlacklistService) Revoke(jti string, userID uuid.UUID, expiresAt time.Time) error {
+	entry := models.TokenBlacklist{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error; err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cache[jti] = blacklistEntry{revoked: true, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IsRevoked reports whether the token with the given jti has been revoked.
+// Database errors are reported as revoked so the caller fails closed.
+func (s *TokenBlacklistService) IsRevoked(jti string) bool {
+	now := time.Now()
+
+	s.mu.RLock()
+	entry, ok := s.cache[jti]
+	s.mu.RUnlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		return entry.revoked
+	}
+
+	// Find instead of First so the common "not revoked" case doesn't log record-not-found
+	var revoked []models.TokenBlacklist
+	if err := s.db.Where("jti = ?", jti).Limit(1).Find(&revoked).Error; err != nil {
+		log.Printf("Token blacklist lookup failed: %v", err)
+		return true
+	}
+
+	if len(revoked) > 0 {
+		// Revoked tokens never become valid again; keep them until they expire
+		entry = blacklistEntry{revoked: true, expiresAt: revoked[0].ExpiresAt}
+	} else {
+		entry = blacklistEntry{revoked: false, expiresAt: now.Add(notRevokedCacheTTL)}
+	}
+
+	s.mu.Lock()
+	s.cache[jti] = entry
+	s.mu.Unlock()
+
+	return entry.revoked
+}
+
+// PurgeExpired deletes blacklist rows and cache entries for tokens past their expiry
+func (s *TokenBlacklistService) PurgeExpired() (int64, error) {
+	now := time.Now()
+
+	result := s.db.Where("expires_at < ?", now).Delete(&models.TokenBlacklist{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	s.mu.Lock()
+	for jti, entry := range s.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(s.cache, jti)
+		}
+	}
+	s.mu.Unlock()
+
+	return result.RowsAffected, nil
+}
+
+// StartSweeper periodically purges expired blacklist entries in the background
+func (s *TokenBlacklistService) StartSweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := s.PurgeExpired()
+			if err != nil {
+				log.Printf("Token blacklist sweep failed: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Token blacklist sweep removed %d expired entries", purged)
+			}
+		}
+	}()
+}
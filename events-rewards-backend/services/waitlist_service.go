@@ -0,0 +1,221 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/Hritikpandey-ops/events-rewards-backend/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultWaitlistClaimWindow is how long a promoted user has to confirm their
+// seat when the event does not set its own claim window
+const DefaultWaitlistClaimWindow = 24 * time.Hour
+
+// waitlistOrder is the order in which waitlisted users are offered freed seats
+const waitlistOrder = "registration_date, id"
+
+// WaitlistService hands freed event seats to the next person on the waitlist
+// and takes them back again when the offer is not confirmed in time.
+type WaitlistService struct {
+	db  *gorm.DB
+	bus *EventBus
+}
+
+func NewWaitlistService(db *gorm.DB, bus *EventBus) *WaitlistService {
+	return &WaitlistService{db: db, bus: bus}
+}
+
+// ClaimWindow returns how long a promoted user has to confirm a seat for event
+func ClaimWindow(event *models.Event) time.Duration {
+	if event.WaitlistClaimHours != nil && *event.WaitlistClaimHours > 0 {
+		return time.Duration(*event.WaitlistClaimHours) * time.Hour
+	}
+	return DefaultWaitlistClaimWindow
+}
+
+// ReleaseSeat gives up one seat on the event. The seat is offered to the
+// longest-waiting user if there is one, otherwise the participant count drops.
+// It must run in the same transaction that removed the seat holder, and it
+// locks the event row so concurrent releases promote different users. The
+// promoted registration, if any, should be passed to AnnouncePromotion once
+// the transaction commits.
+func (s *WaitlistService) ReleaseSeat(tx *gorm.DB, eventID uuid.UUID, now time.Time) (*models.EventRegistration, error) {
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", eventID).First(&event).Error; err != nil {
+		return nil, err
+	}
+
+	var waiting []models.EventRegistration
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ? AND status = ?", eventID, models.RegistrationStatusWaitlisted).
+		Order(waitlistOrder).
+		Limit(1).
+		Find(&waiting).Error; err != nil {
+		return nil, err
+	}
+
+	if len(waiting) == 0 {
+		err := tx.Model(&models.Event{}).
+			Where("id = ?", eventID).
+			Update("current_participants", gorm.Expr("GREATEST(current_participants - 1, 0)")).Error
+		return nil, err
+	}
+
+	// The seat passes straight to the promoted user, so the participant count is unchanged
+	promoted := waiting[0]
+	claimExpiresAt := now.Add(ClaimWindow(&event))
+	if err := tx.Model(&promoted).Updates(map[string]interface{}{
+		"status":           models.RegistrationStatusOffered,
+		"promoted_at":      now,
+		"claim_expires_at": claimExpiresAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+	promoted.Status = models.RegistrationStatusOffered
+	promoted.PromotedAt = &now
+	promoted.ClaimExpiresAt = &claimExpiresAt
+
+	return &promoted, nil
+}
+
+// FillOpenSeats offers any seats the event has free to the users at the front
+// of its waitlist, as happens when its capacity is raised. It locks the event
+// row like ReleaseSeat and returns the promoted registrations, which should be
+// passed to AnnouncePromotion once the transaction commits.
+func (s *WaitlistService) FillOpenSeats(tx *gorm.DB, eventID uuid.UUID, now time.Time) ([]models.EventRegistration, error) {
+	var event models.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", eventID).First(&event).Error; err != nil {
+		return nil, err
+	}
+
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ? AND status = ?", eventID, models.RegistrationStatusWaitlisted).
+		Order(waitlistOrder)
+	if event.MaxParticipants != nil {
+		open := *event.MaxParticipants - event.CurrentParticipants
+		if open <= 0 {
+			return nil, nil
+		}
+		query = query.Limit(open)
+	}
+
+	var promoted []models.EventRegistration
+	if err := query.Find(&promoted).Error; err != nil {
+		return nil, err
+	}
+	if len(promoted) == 0 {
+		return nil, nil
+	}
+
+	claimExpiresAt := now.Add(ClaimWindow(&event))
+	ids := make([]uuid.UUID, 0, len(promoted))
+	for i := range promoted {
+		ids = append(ids, promoted[i].ID)
+		promoted[i].Status = models.RegistrationStatusOffered
+		promoted[i].PromotedAt = &now
+		promoted[i].ClaimExpiresAt = &claimExpiresAt
+	}
+	if err := tx.Model(&models.EventRegistration{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":           models.RegistrationStatusOffered,
+		"promoted_at":      now,
+		"claim_expires_at": claimExpiresAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// Offered seats are held for the promoted users, so they count as taken
+	err := tx.Model(&models.Event{}).
+		Where("id = ?", eventID).
+		Update("current_participants", gorm.Expr("current_participants + ?", len(promoted))).Error
+	return promoted, err
+}
+
+// AnnouncePromotion publishes that a waitlisted user has been offered a seat
+func (s *WaitlistService) AnnouncePromotion(promoted *models.EventRegistration) {
+	if promoted == nil {
+		return
+	}
+	s.bus.Publish(EventWaitlistPromoted, map[string]interface{}{
+		"user_id":          promoted.UserID,
+		"event_id":         promoted.EventID,
+		"registration_id":  promoted.ID,
+		"claim_expires_at": promoted.ClaimExpiresAt,
+	})
+}
+
+// Position returns the 1-based place of a waitlisted registration in its event's queue
+func (s *WaitlistService) Position(registration *models.EventRegistration) (int64, error) {
+	var ahead int64
+	err := s.db.Model(&models.EventRegistration{}).
+		Where("event_id = ? AND status = ?", registration.EventID, models.RegistrationStatusWaitlisted).
+		Where("registration_date < ? OR (registration_date = ? AND id < ?)",
+			registration.RegistrationDate, registration.RegistrationDate, registration.ID).
+		Count(&ahead).Error
+	return ahead + 1, err
+}
+
+// ExpireOffers withdraws seat offers whose claim window has passed and offers
+// each seat to the next user in line. It returns the number of offers withdrawn.
+func (s *WaitlistService) ExpireOffers() (int, error) {
+	expired := 0
+
+	for {
+		var promoted *models.EventRegistration
+		found := false
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			now := time.Now()
+
+			var offers []models.EventRegistration
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("status = ? AND claim_expires_at < ?", models.RegistrationStatusOffered, now).
+				Limit(1).
+				Find(&offers).Error; err != nil {
+				return err
+			}
+			if len(offers) == 0 {
+				return nil
+			}
+			found = true
+
+			if err := tx.Delete(&offers[0]).Error; err != nil {
+				return err
+			}
+
+			var err error
+			promoted, err = s.ReleaseSeat(tx, offers[0].EventID, now)
+			return err
+		})
+		if err != nil {
+			return expired, err
+		}
+		if !found {
+			return expired, nil
+		}
+
+		expired++
+		s.AnnouncePromotion(promoted)
+	}
+}
+
+// StartWorker periodically withdraws expired seat offers in the background
+func (s *WaitlistService) StartWorker(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := s.ExpireOffers()
+			if err != nil {
+				log.Printf("Waitlist offer expiry failed: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Withdrew %d expired waitlist offers", expired)
+			}
+		}
+	}()
+}